@@ -40,8 +40,8 @@ type CaptchaToken struct {
 
 type RecaptchaConfig struct {
 	Enabled     bool    `env:"RECAPTCHA_ENABLED,default=false"`
-	SecretV3    string  `env:"RECAPTCHA_V3_SECRET"`
-	SecretV2    string  `env:"RECAPTCHA_V2_SECRET"`
+	SecretV3    string  `env:"RECAPTCHA_V3_SECRET" secret:"true"`
+	SecretV2    string  `env:"RECAPTCHA_V2_SECRET" secret:"true"`
 	ThresholdV3 float64 `env:"RECAPTCHA_V3_THRESHOLD,default=0.5"`
 }
 
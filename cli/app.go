@@ -3,8 +3,13 @@ package cli
 import (
 	"context"
 	"os"
+	"slices"
 	"sort"
+	"sync/atomic"
 
+	"braces.dev/errtrace"
+	"github.com/go-modulus/modulus/config"
+	"github.com/go-modulus/modulus/errors/errsys"
 	"github.com/urfave/cli/v3"
 	"go.uber.org/fx"
 )
@@ -35,13 +40,14 @@ func NewApp(params StartCliParams) App {
 		usage = params.Config.Usage
 	}
 	commands := params.Commands
-	addGlobalFlagsToAllSubcommands(commands, params.Config.GlobalFlags)
+	globalFlags := withSetFlag(params.Config.GlobalFlags)
+	addGlobalFlagsToAllSubcommands(commands, globalFlags)
 	app := &cli.Command{
 		Usage:                 usage,
 		Version:               params.Config.Version,
 		DefaultCommand:        params.Config.DefaultCommand,
 		Commands:              commands,
-		Flags:                 params.Config.GlobalFlags,
+		Flags:                 globalFlags,
 		EnableShellCompletion: true,
 		Suggest:               true,
 	}
@@ -64,6 +70,59 @@ func NewApp(params StartCliParams) App {
 	return app
 }
 
+var ErrSetFlagNotApplied = errsys.New(
+	"set flag is not applied",
+	"The --set flags are applied only by cli.LoadEnv. Call it in main instead of config.LoadDefaultEnv",
+)
+
+// envLoaded is true after LoadEnv has applied the "--set" flags.
+var envLoaded atomic.Bool
+
+// NewSetFlag returns the "--set KEY=VALUE" flag overriding env variables.
+// The values are applied by LoadEnv before the modules are created,
+// so the flag is only registered to be accepted by the commands.
+// If LoadEnv has not been called, the command fails with ErrSetFlagNotApplied instead of ignoring the values.
+func NewSetFlag() cli.Flag {
+	return &cli.StringSliceFlag{
+		Name:  "set",
+		Usage: "Override the env variable, e.g. --set LOGGER_LEVEL=info. It has priority over the environment and .env files",
+		Action: func(context.Context, *cli.Command, []string) error {
+			if !envLoaded.Load() {
+				return errtrace.Wrap(ErrSetFlagNotApplied)
+			}
+			return nil
+		},
+	}
+}
+
+// LoadEnv sets the env variables like config.LoadDefaultEnv and applies the values of the "--set KEY=VALUE" flags
+// of the command line arguments over them. Call it in main before the modules are created:
+//
+//	if err := cli.LoadEnv(os.Args[1:]); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// "--set APP_ENV=..." selects the .env chain as well. An invalid flag value returns config.ErrInvalidSetFlag.
+// Don't call it if a command has its own flag named "set", the flag is taken by LoadEnv in all commands.
+func LoadEnv(args []string) error {
+	err := config.NewLoader().WithOverrides(config.NewArgsSource(args)).Load()
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	envLoaded.Store(true)
+	return nil
+}
+
+// withSetFlag adds the "--set" flag to the global flags if it is not there.
+func withSetFlag(flags []cli.Flag) []cli.Flag {
+	for _, flag := range flags {
+		if slices.Contains(flag.Names(), "set") {
+			return flags
+		}
+	}
+	return append(slices.Clone(flags), NewSetFlag())
+}
+
 func addGlobalFlagsToAllSubcommands(
 	commands []*cli.Command,
 	flags []cli.Flag,
@@ -0,0 +1,48 @@
+package cli_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/go-modulus/modulus/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	urfave "github.com/urfave/cli/v3"
+)
+
+// TestNewSetFlag is not parallel: LoadEnv changes the process environment.
+func TestNewSetFlag(t *testing.T) {
+	t.Setenv("CLI_TEST_SET", "")
+	var value string
+	newCommand := func() *urfave.Command {
+		return &urfave.Command{
+			Name:      "app",
+			Writer:    io.Discard,
+			ErrWriter: io.Discard,
+			Flags:     []urfave.Flag{cli.NewSetFlag()},
+			Action: func(context.Context, *urfave.Command) error {
+				value = "called"
+				return nil
+			},
+		}
+	}
+	args := []string{"app", "--set", "CLI_TEST_SET=1"}
+
+	err := newCommand().Run(context.Background(), args)
+
+	t.Log("When the --set flag is used without LoadEnv")
+	t.Log("	Then the command fails instead of ignoring the values")
+	require.ErrorIs(t, err, cli.ErrSetFlagNotApplied)
+	assert.Empty(t, value)
+
+	require.NoError(t, cli.LoadEnv(args[1:]))
+	err = newCommand().Run(context.Background(), args)
+
+	t.Log("When the --set flag is applied by LoadEnv")
+	t.Log("	Then the command runs with the overridden variable")
+	require.NoError(t, err)
+	assert.Equal(t, "called", value)
+	assert.Equal(t, "1", os.Getenv("CLI_TEST_SET"))
+}
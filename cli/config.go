@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"braces.dev/errtrace"
+	"github.com/go-modulus/modulus/config"
+	"github.com/go-modulus/modulus/errors/errsys"
+	"github.com/go-modulus/modulus/module"
+	"github.com/urfave/cli/v3"
+	"go.uber.org/fx"
+)
+
+var ErrStaleConfigDocs = errsys.New(
+	"config docs are stale",
+	`The generated config files differ from the committed ones. Run the "config docs" command to update them`,
+)
+
+const (
+	// ConfigSourceCode is the source of a value set in the config struct passed to InitConfig.
+	ConfigSourceCode = "code"
+)
+
+type ConfigCommandParams struct {
+	fx.In
+
+	Graphs []*module.Graph `group:"module.graphs"`
+}
+
+// ConfigVariable is an env variable read by the application.
+// Source is config.EnvSourceDefault, ConfigSourceCode, config.EnvSourceProcess or the name of the .env file.
+type ConfigVariable struct {
+	Module  string `json:"module"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Default string `json:"default"`
+	Source  string `json:"source"`
+	Comment string `json:"comment,omitempty"`
+	Secret  bool   `json:"secret,omitempty"`
+}
+
+type ConfigCommand struct {
+	graph *module.Graph
+}
+
+func NewConfigCommand(params ConfigCommandParams) *ConfigCommand {
+	return &ConfigCommand{graph: mergeGraphs(params.Graphs)}
+}
+
+func NewConfigCliCommand(c *ConfigCommand) *cli.Command {
+	return &cli.Command{
+		Name:  "config",
+		Usage: "Inspect the configuration of the application",
+		Commands: []*cli.Command{
+			{
+				Name:  "show",
+				Usage: "Print every env variable read by the modules with its effective value and source",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "Output format: table or json",
+						Value:   "table",
+					},
+				},
+				Action: c.Show,
+			},
+			{
+				Name:  "docs",
+				Usage: "Generate the .env example and the Markdown reference of the env variables of all modules",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "env-example",
+						Usage: "Path of the generated .env example. Leave empty to skip it",
+						Value: ".env.example",
+					},
+					&cli.StringFlag{
+						Name:  "markdown",
+						Usage: "Path of the generated Markdown reference. Leave empty to skip it",
+						Value: "docs/config-reference.md",
+					},
+					&cli.BoolFlag{
+						Name:  "check",
+						Usage: "Do not write the files, fail if they differ from the generated ones",
+					},
+				},
+				Action: c.Docs,
+			},
+		},
+	}
+}
+
+// Variables returns the env variables of all modules. Values of secrets are masked.
+func (c *ConfigCommand) Variables() []ConfigVariable {
+	var vars []ConfigVariable
+	for _, m := range c.graph.Modules {
+		for _, envVar := range m.EnvVars {
+			vars = append(
+				vars, ConfigVariable{
+					Module:  m.Name,
+					Key:     envVar.Key,
+					Value:   envVar.Value,
+					Default: envVar.Default,
+					Source:  configSource(envVar),
+					Comment: envVar.Comment,
+					Secret:  envVar.Secret,
+				},
+			)
+		}
+	}
+	return vars
+}
+
+// configSource finds where the value of the variable comes from.
+// The env source is reported only if the config value is not set in code.
+func configSource(envVar module.GraphEnvVar) string {
+	if envVar.InCode {
+		return ConfigSourceCode
+	}
+	return config.EnvSource(envVar.Key)
+}
+
+func (c *ConfigCommand) Show(ctx context.Context, cmd *cli.Command) error {
+	w := cmd.Root().Writer
+	vars := c.Variables()
+	switch cmd.String("format") {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "MODULE\tKEY\tVALUE\tDEFAULT\tSOURCE")
+		for _, v := range vars {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", v.Module, v.Key, v.Value, v.Default, v.Source)
+		}
+		return errtrace.Wrap(tw.Flush())
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return errtrace.Wrap(encoder.Encode(vars))
+	default:
+		return errtrace.Errorf(`unknown config format "%s". Use "table" or "json"`, cmd.String("format"))
+	}
+}
+
+// Docs writes the .env example and the Markdown reference generated from the env variables of all modules.
+// In the check mode, it returns ErrStaleConfigDocs listing the files that differ from the generated content.
+func (c *ConfigCommand) Docs(ctx context.Context, cmd *cli.Command) error {
+	docs := []struct {
+		path  string
+		write func(w io.Writer) error
+	}{
+		{path: cmd.String("env-example"), write: c.graph.WriteEnvExample},
+		{path: cmd.String("markdown"), write: c.graph.WriteConfigReference},
+	}
+	var stale []string
+	for _, doc := range docs {
+		if doc.path == "" {
+			continue
+		}
+		var b bytes.Buffer
+		if err := doc.write(&b); err != nil {
+			return errtrace.Wrap(err)
+		}
+		if cmd.Bool("check") {
+			current, err := os.ReadFile(doc.path)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return errtrace.Wrap(err)
+			}
+			if !bytes.Equal(current, b.Bytes()) {
+				stale = append(stale, doc.path)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(doc.path), 0755); err != nil {
+			return errtrace.Wrap(err)
+		}
+		if err := os.WriteFile(doc.path, b.Bytes(), 0644); err != nil {
+			return errtrace.Wrap(err)
+		}
+		fmt.Fprintln(cmd.Root().Writer, "Written", doc.path)
+	}
+	if len(stale) > 0 {
+		return errtrace.Errorf("%s: %w", strings.Join(stale, ", "), ErrStaleConfigDocs)
+	}
+	return nil
+}
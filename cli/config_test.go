@@ -0,0 +1,141 @@
+package cli_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/go-modulus/modulus/cli"
+	"github.com/go-modulus/modulus/config"
+	"github.com/go-modulus/modulus/module"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	urfave "github.com/urfave/cli/v3"
+)
+
+type sourcesConfig struct {
+	Timeout time.Duration     `env:"CLI_TEST_TIMEOUT, default=1m"`
+	Size    datasize.ByteSize `env:"CLI_TEST_SIZE, default=5mb"`
+	Name    string            `env:"CLI_TEST_NAME, default=app"`
+	Retries int               `env:"CLI_TEST_RETRIES, default=3"`
+}
+
+func TestConfigCommand_Variables(t *testing.T) {
+	t.Parallel()
+	mod := module.NewModule("test").InitConfig(sourcesConfig{Name: "custom", Retries: 3})
+	command := cli.NewConfigCommand(cli.ConfigCommandParams{Graphs: []*module.Graph{module.NewGraph(mod)}})
+
+	sources := make(map[string]string)
+	for _, v := range command.Variables() {
+		sources[v.Key] = v.Source
+	}
+
+	t.Log("When the variables of a config with non-string defaults are listed")
+	t.Log("	Then the defaults formatted differently from the tags are not reported as set in code")
+	t.Log("	And the values passed to InitConfig are reported as set in code even if they equal the defaults")
+	assert.Equal(
+		t, map[string]string{
+			"CLI_TEST_TIMEOUT": config.EnvSourceDefault,
+			"CLI_TEST_SIZE":    config.EnvSourceDefault,
+			"CLI_TEST_NAME":    cli.ConfigSourceCode,
+			"CLI_TEST_RETRIES": cli.ConfigSourceCode,
+		}, sources,
+	)
+}
+
+func TestConfigCommand_Docs(t *testing.T) {
+	t.Parallel()
+	type docsConfig struct {
+		Level string `env:"CLI_TEST_DOCS_LEVEL, default=info" comment:"docs"`
+	}
+	mod := module.NewModule("docs").InitConfig(docsConfig{})
+	command := cli.NewConfigCommand(cli.ConfigCommandParams{Graphs: []*module.Graph{module.NewGraph(mod)}})
+	run := func(args ...string) error {
+		app := &urfave.Command{
+			Name:      "app",
+			Writer:    io.Discard,
+			ErrWriter: io.Discard,
+			Commands:  []*urfave.Command{cli.NewConfigCliCommand(command)},
+		}
+		return app.Run(context.Background(), append([]string{"app", "config", "docs"}, args...))
+	}
+	newPaths := func(t *testing.T) (string, string) {
+		dir := t.TempDir()
+		return filepath.Join(dir, ".env.example"), filepath.Join(dir, "docs", "config-reference.md")
+	}
+
+	t.Run(
+		"write and check up-to-date files", func(t *testing.T) {
+			t.Parallel()
+			envPath, mdPath := newPaths(t)
+
+			err := run("--env-example", envPath, "--markdown", mdPath)
+			require.NoError(t, err)
+			written, err := os.ReadFile(envPath)
+			require.NoError(t, err)
+			err = run("--env-example", envPath, "--markdown", mdPath, "--check")
+
+			t.Log("When the docs are written and checked")
+			t.Log("	Then the files contain the module sections and the check passes")
+			require.NoError(t, err)
+			assert.Equal(
+				t, `# Generated by the "config docs" command. Do not edit.
+
+# --- docs ---
+# docs
+CLI_TEST_DOCS_LEVEL=info
+`, string(written),
+			)
+			assert.FileExists(t, mdPath)
+
+			err = run("--env-example", envPath, "--markdown", mdPath)
+			require.NoError(t, err)
+			rewritten, err := os.ReadFile(envPath)
+			require.NoError(t, err)
+
+			t.Log("When the docs are written again")
+			t.Log("	Then the content is the same")
+			assert.Equal(t, string(written), string(rewritten))
+		},
+	)
+
+	t.Run(
+		"check stale files", func(t *testing.T) {
+			t.Parallel()
+			envPath, mdPath := newPaths(t)
+			require.NoError(t, run("--env-example", envPath, "--markdown", mdPath))
+			stale := []byte("# --- docs ---\nCLI_TEST_DOCS_LEVEL=debug\n")
+			require.NoError(t, os.WriteFile(envPath, stale, 0644))
+
+			err := run("--env-example", envPath, "--markdown", mdPath, "--check")
+
+			t.Log("When a changed file is checked")
+			t.Log("	Then the check fails with the stale file and doesn't write it")
+			require.ErrorIs(t, err, cli.ErrStaleConfigDocs)
+			assert.Contains(t, err.Error(), envPath)
+			assert.NotContains(t, err.Error(), mdPath)
+			content, err := os.ReadFile(envPath)
+			require.NoError(t, err)
+			assert.Equal(t, string(stale), string(content))
+		},
+	)
+
+	t.Run(
+		"check missing files", func(t *testing.T) {
+			t.Parallel()
+			envPath, mdPath := newPaths(t)
+
+			err := run("--env-example", envPath, "--markdown", mdPath, "--check")
+
+			t.Log("When the files are not generated yet")
+			t.Log("	Then the check fails and no files are written")
+			require.ErrorIs(t, err, cli.ErrStaleConfigDocs)
+			assert.NoFileExists(t, envPath)
+			assert.NoFileExists(t, mdPath)
+		},
+	)
+}
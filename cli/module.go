@@ -9,6 +9,12 @@ func NewModule(options ...module.Option) *module.Module {
 	return module.NewModule("cli").
 		AddProviders(
 			NewRunner,
+			NewModulesCommand,
+			NewConfigCommand,
+		).
+		AddCliCommands(
+			NewModulesCliCommand,
+			NewConfigCliCommand,
 		).
 		SetOverriddenProvider("cli.App", NewApp).
 		SetOverriddenProvider("cli.ErrorHandler", NewLogErrorHandler).
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+
+	"braces.dev/errtrace"
+	"github.com/go-modulus/modulus/module"
+	"github.com/urfave/cli/v3"
+	"go.uber.org/fx"
+)
+
+type ModulesCommandParams struct {
+	fx.In
+
+	Graphs []*module.Graph `group:"module.graphs"`
+}
+
+type ModulesCommand struct {
+	graph *module.Graph
+}
+
+func NewModulesCommand(params ModulesCommandParams) *ModulesCommand {
+	return &ModulesCommand{graph: mergeGraphs(params.Graphs)}
+}
+
+// mergeGraphs joins the graphs of several BuildFx calls. A module is taken from the first graph containing it.
+// Values of an fx group come in a random order, so the graphs are sorted by the name of their first module
+// to print the same output on every run.
+func mergeGraphs(graphs []*module.Graph) *module.Graph {
+	graphs = slices.Clone(graphs)
+	sort.SliceStable(
+		graphs, func(i, j int) bool {
+			return firstModuleName(graphs[i]) < firstModuleName(graphs[j])
+		},
+	)
+	graph := &module.Graph{}
+	modules := make([][]module.GraphModule, 0, len(graphs))
+	allModules := make([][]module.GraphModule, 0, len(graphs))
+	for _, g := range graphs {
+		modules = append(modules, g.Modules)
+		allModules = append(allModules, g.AllModules)
+	}
+	graph.Modules = mergeModules(modules)
+	graph.AllModules = mergeModules(allModules)
+	return graph
+}
+
+// firstModuleName returns the name of the first root module of the BuildFx call, even if it is disabled.
+func firstModuleName(graph *module.Graph) string {
+	for _, modules := range [][]module.GraphModule{graph.AllModules, graph.Modules} {
+		if len(modules) > 0 {
+			return modules[0].Name
+		}
+	}
+	return ""
+}
+
+func mergeModules(lists [][]module.GraphModule) []module.GraphModule {
+	var result []module.GraphModule
+	seen := make(map[string]struct{})
+	for _, modules := range lists {
+		for _, m := range modules {
+			if _, ok := seen[m.Name]; ok {
+				continue
+			}
+			seen[m.Name] = struct{}{}
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+func NewModulesCliCommand(c *ModulesCommand) *cli.Command {
+	return &cli.Command{
+		Name:  "modules",
+		Usage: "Inspect the modules of the application",
+		Commands: []*cli.Command{
+			{
+				Name:  "graph",
+				Usage: "Print the module dependency graph",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "Output format: dot, mermaid or json",
+						Value:   "dot",
+					},
+				},
+				Action: c.Graph,
+			},
+		},
+	}
+}
+
+func (c *ModulesCommand) Graph(ctx context.Context, cmd *cli.Command) error {
+	w := cmd.Root().Writer
+	switch cmd.String("format") {
+	case "dot":
+		return c.graph.WriteDOT(w)
+	case "mermaid":
+		return c.graph.WriteMermaid(w)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(c.graph)
+	default:
+		return errtrace.Errorf(`unknown graph format "%s". Use "dot", "mermaid" or "json"`, cmd.String("format"))
+	}
+}
@@ -0,0 +1,96 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/go-modulus/modulus/cli"
+	"github.com/go-modulus/modulus/module"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	urfave "github.com/urfave/cli/v3"
+	"go.uber.org/fx"
+)
+
+func TestModulesCommand_Graph(t *testing.T) {
+	t.Parallel()
+	shared := module.NewModule("shared")
+	var app cli.App
+	fxApp := fx.New(
+		fx.NopLogger,
+		fx.Supply(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		module.BuildFx(cli.NewModule(), shared),
+		module.BuildFx(module.NewModule("second").AddDependencies(shared)),
+		fx.Populate(&app),
+	)
+	require.NoError(t, fxApp.Err())
+	run := func(t *testing.T, format string) string {
+		var b bytes.Buffer
+		command := app.(*urfave.Command)
+		command.Writer = &b
+		err := command.Run(context.Background(), []string{"app", "modules", "graph", "--format", format})
+		require.NoError(t, err)
+		return b.String()
+	}
+
+	t.Run(
+		"json", func(t *testing.T) {
+			var graph module.Graph
+			require.NoError(t, json.Unmarshal([]byte(run(t, "json")), &graph))
+
+			t.Log("When the graph of two BuildFx calls is printed as JSON")
+			t.Log("	Then the modules of both graphs are listed once in the order of the calls")
+			names := make([]string, 0, len(graph.Modules))
+			for _, m := range graph.Modules {
+				names = append(names, m.Name)
+			}
+			assert.Equal(t, []string{"cli", "shared", "second"}, names)
+			assert.Equal(t, []string{"shared"}, graph.Modules[2].Dependencies)
+		},
+	)
+
+	t.Run(
+		"dot", func(t *testing.T) {
+			output := run(t, "dot")
+
+			t.Log("When the graph is printed in the DOT format")
+			t.Log("	Then the shared module is a single node with the edge from the second graph")
+			assert.True(t, strings.HasPrefix(output, "digraph modules {\n"))
+			assert.Equal(t, 1, strings.Count(output, `"shared" [label=`))
+			assert.Contains(t, output, `"second" -> "shared";`)
+		},
+	)
+
+	t.Run(
+		"mermaid", func(t *testing.T) {
+			output := run(t, "mermaid")
+
+			t.Log("When the graph is printed as a Mermaid flowchart")
+			t.Log("	Then the modules of both graphs are nodes linked by their dependencies")
+			assert.True(t, strings.HasPrefix(output, "flowchart TD\n"))
+			assert.Contains(t, output, "\tm1[\"shared\"]\n")
+			assert.Contains(t, output, "\tm2[\"second\"]\n")
+			assert.Contains(t, output, "\tm2 --> m1\n")
+		},
+	)
+
+	t.Run(
+		"unknown format", func(t *testing.T) {
+			command := app.(*urfave.Command)
+			command.Writer = io.Discard
+			command.ErrWriter = io.Discard
+
+			err := command.Run(context.Background(), []string{"app", "modules", "graph", "--format", "svg"})
+
+			t.Log("When an unknown format is requested")
+			t.Log("	Then the command fails")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), `unknown graph format "svg"`)
+		},
+	)
+}
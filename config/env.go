@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sync"
 
 	"github.com/fatih/color"
 	"github.com/subosito/gotenv"
@@ -11,28 +13,76 @@ import (
 	_ "golang.org/x/text/message"
 )
 
+const (
+	// EnvSourceDefault is the source of a variable that is not set in the environment.
+	EnvSourceDefault = "default"
+	// EnvSourceProcess is the source of a variable set in the process environment, not by a loaded .env file.
+	EnvSourceProcess = "env"
+)
+
+var (
+	sourcesMu sync.RWMutex
+	// sources holds the source and the value for each variable set by LoadEnv or a Loader.
+	sources = make(map[string]envSource)
+)
+
+type envSource struct {
+	// name is the name of the source, e.g. the name of the .env file
+	name  string
+	value string
+	// loader is the Loader that set the variable. It is nil for the variables set by LoadEnv.
+	loader *Loader
+}
+
+// LoadDefaultEnv sets the env variables from the sources of NewLoader: the .env chain and the config files from CONFIG_FILES.
+// Variables of the process environment are not overridden.
+// It panics if a source cannot be read. Use cli.LoadEnv to apply the "--set" flags of the command line too.
 func LoadDefaultEnv() {
+	if err := NewLoader().Load(); err != nil {
+		panic(err)
+	}
+}
+
+// DefaultEnvFiles returns the paths of the .env files loaded by LoadDefaultEnv in the order of their priority.
+func DefaultEnvFiles() []string {
+	return defaultEnvFiles(os.Getenv)
+}
+
+// defaultEnvFiles returns the .env chain for the CONFIG_DIR and APP_ENV variables returned by getenv.
+func defaultEnvFiles(getenv func(key string) string) []string {
 	currentDir, err := os.Getwd()
 	if err != nil {
 		panic(err)
 	}
-	configDir := os.Getenv("CONFIG_DIR")
-	if configDir != "" {
+	if configDir := getenv("CONFIG_DIR"); configDir != "" {
 		currentDir = configDir
 	}
-	env := os.Getenv("APP_ENV")
+	env := getenv("APP_ENV")
 	if env == "" {
-		env = "local"
+		env = EnvLocal.String()
+	}
+	return []string{
+		currentDir + "/.env." + env,
+		currentDir + "/.env",
+	}
+}
+
+// ReloadDefaultEnv reads the sources of the last loaded Loader again (the ones of LoadDefaultEnv if nothing is loaded).
+// Variables set by the sources are updated or unset if they are removed from the sources.
+// Variables set by the process environment are kept.
+func ReloadDefaultEnv() error {
+	loaderMu.Lock()
+	loader := lastLoader
+	loaderMu.Unlock()
+	if loader == nil {
+		loader = NewLoader()
 	}
-	// load ".env.{APP_ENV}". This is the .env file with overrides of default variables.
-	// It is loaded first so that it can override the default variables
-	LoadEnv(currentDir, env, false)
-	// load default ".env". This is the .env file with default variables.
-	LoadEnv(currentDir, "", false)
+	return loader.Load()
 }
 
+// IsProd returns true if the application is running in the EnvProd environment.
 func IsProd() bool {
-	return os.Getenv("APP_ENV") == "prod"
+	return CurrentEnvironment().IsProd()
 }
 
 func LoadEnv(basePath string, env string, override bool) {
@@ -64,16 +114,44 @@ func load(filenames []string, override bool) bool {
 			}
 			panic(err)
 		}
-		if override {
-			err = gotenv.OverApply(f)
-		} else {
-			err = gotenv.Apply(f)
-		}
+		env, err := gotenv.StrictParse(f)
 		f.Close()
 		if err != nil {
 			panic(err)
 		}
+		for key, value := range env {
+			setEnv(filepath.Base(filename), key, value, override)
+		}
 	}
 
 	return true
 }
+
+func setEnv(file, key, value string, override bool) {
+	if _, ok := os.LookupEnv(key); ok && !override {
+		return
+	}
+	if err := os.Setenv(key, value); err != nil {
+		panic(err)
+	}
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	sources[key] = envSource{name: file, value: value}
+}
+
+// EnvSource returns where the current value of the variable comes from:
+// the name of the source that set it (e.g. ".env", ".env.local", "config.yaml" or "--set"),
+// EnvSourceProcess if it is set in the process environment or EnvSourceDefault if it is not set.
+func EnvSource(key string) string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return EnvSourceDefault
+	}
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	source, ok := sources[key]
+	if !ok || source.value != value {
+		return EnvSourceProcess
+	}
+	return source.name
+}
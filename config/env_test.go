@@ -0,0 +1,60 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-modulus/modulus/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvSource(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("SOURCE_TEST_A=a\nSOURCE_TEST_B=b\nSOURCE_TEST_C=c\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env.test"), []byte("SOURCE_TEST_B=test\n"), 0644))
+	t.Setenv("SOURCE_TEST_C", "process")
+	for _, key := range []string{"SOURCE_TEST_A", "SOURCE_TEST_B"} {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+
+	config.LoadEnv(dir, "test", false)
+	config.LoadEnv(dir, "", false)
+
+	assert.Equal(t, ".env", config.EnvSource("SOURCE_TEST_A"))
+	assert.Equal(t, ".env.test", config.EnvSource("SOURCE_TEST_B"))
+	assert.Equal(t, "test", os.Getenv("SOURCE_TEST_B"))
+	assert.Equal(t, config.EnvSourceProcess, config.EnvSource("SOURCE_TEST_C"))
+	assert.Equal(t, config.EnvSourceDefault, config.EnvSource("SOURCE_TEST_UNKNOWN"))
+
+	t.Setenv("SOURCE_TEST_A", "changed")
+	assert.Equal(t, config.EnvSourceProcess, config.EnvSource("SOURCE_TEST_A"))
+}
+
+func TestReloadDefaultEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONFIG_DIR", dir)
+	t.Setenv("APP_ENV", "reload")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("RELOAD_TEST_A=a\nRELOAD_TEST_B=b\nRELOAD_TEST_C=c\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env.reload"), []byte("RELOAD_TEST_A=reload\n"), 0644))
+	t.Setenv("RELOAD_TEST_C", "process")
+	for _, key := range []string{"RELOAD_TEST_A", "RELOAD_TEST_B"} {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+	config.LoadDefaultEnv()
+	require.Equal(t, "reload", os.Getenv("RELOAD_TEST_A"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("RELOAD_TEST_A=a\nRELOAD_TEST_C=changed\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env.reload"), []byte("RELOAD_TEST_A=changed\n"), 0644))
+
+	require.NoError(t, config.ReloadDefaultEnv())
+
+	assert.Equal(t, "changed", os.Getenv("RELOAD_TEST_A"))
+	_, ok := os.LookupEnv("RELOAD_TEST_B")
+	assert.False(t, ok)
+	assert.Equal(t, "process", os.Getenv("RELOAD_TEST_C"))
+	assert.Equal(t, []string{dir + "/.env.reload", dir + "/.env"}, config.DefaultEnvFiles())
+}
@@ -0,0 +1,428 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+type envLineKind int
+
+const (
+	envLineBlank envLineKind = iota
+	envLineComment
+	envLineVariable
+	// envLineInvalid is a line that cannot be parsed. It is kept as is.
+	envLineInvalid
+)
+
+// envLine is a line of the .env file. A variable with a multi-line quoted value takes several physical lines.
+// raw holds the original text without the line ending. It is used for rendering until the line is modified.
+type envLine struct {
+	kind envLineKind
+	raw  string
+	eol  string
+
+	// prefix is everything before the value: indentation, "export ", the key and "=".
+	prefix string
+	key    string
+	value  string
+	quote  byte
+	// suffix is everything after the value: spaces and an inline comment.
+	suffix   string
+	modified bool
+}
+
+func (l *envLine) render() string {
+	if !l.modified {
+		return l.raw
+	}
+	return l.prefix + quoteEnvValue(l.value, l.quote) + l.suffix
+}
+
+// EnvFile is a .env document that keeps comments, blank lines, quoting and export prefixes.
+// Lines that are not changed are written back byte by byte.
+type EnvFile struct {
+	lines []*envLine
+}
+
+// ReadEnvFile parses the .env file. A missing file is read as an empty document.
+func ReadEnvFile(path string) (*EnvFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return ParseEnv(content), nil
+}
+
+// ParseEnv parses the content of a .env file. Lines that cannot be parsed are kept unchanged.
+func ParseEnv(content []byte) *EnvFile {
+	f := &EnvFile{}
+	rest := string(content)
+	for len(rest) > 0 {
+		line, eol, next := cutLine(rest)
+		l := &envLine{raw: line, eol: eol}
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			l.kind = envLineBlank
+		case strings.HasPrefix(trimmed, "#"):
+			l.kind = envLineComment
+		default:
+			next = parseVariable(l, next)
+		}
+		f.lines = append(f.lines, l)
+		rest = next
+	}
+	return f
+}
+
+// cutLine returns the first line of s without the line ending, the line ending and the rest of s.
+func cutLine(s string) (string, string, string) {
+	i := strings.IndexByte(s, '\n')
+	if i < 0 {
+		return s, "", ""
+	}
+	line := s[:i]
+	if strings.HasSuffix(line, "\r") {
+		return line[:len(line)-1], "\r\n", s[i+1:]
+	}
+	return line, "\n", s[i+1:]
+}
+
+// parseVariable fills the variable line. Quoted values may continue on the next lines,
+// so it takes the rest of the document and returns what is left after the variable.
+func parseVariable(l *envLine, rest string) string {
+	l.kind = envLineInvalid
+	line := l.raw
+	body := strings.TrimLeft(line, " \t")
+	body, _ = strings.CutPrefix(body, "export ")
+	eq := strings.IndexByte(body, '=')
+	if eq <= 0 {
+		return rest
+	}
+	key := strings.TrimSpace(body[:eq])
+	if !isEnvKey(key) {
+		return rest
+	}
+	prefixLen := len(line) - len(body) + eq + 1
+	afterEq := line[prefixLen:]
+	spaces := len(afterEq) - len(strings.TrimLeft(afterEq, " \t"))
+	prefixLen += spaces
+	valuePart := line[prefixLen:]
+
+	l.prefix = line[:prefixLen]
+	l.key = key
+
+	if len(valuePart) > 0 && (valuePart[0] == '"' || valuePart[0] == '\'') {
+		quote := valuePart[0]
+		text := valuePart + l.eol + rest
+		end := closingQuote(text, quote)
+		if end < 0 {
+			// unterminated quote, the line is kept as is
+			return rest
+		}
+		// the variable ends at the end of the physical line with the closing quote
+		suffix, eol, next := cutLine(text[end+1:])
+		l.kind = envLineVariable
+		l.quote = quote
+		l.value = unquoteEnvValue(text[1:end], quote)
+		l.suffix = suffix
+		l.raw = line[:prefixLen] + text[:end+1] + suffix
+		l.eol = eol
+		return next
+	}
+
+	value := valuePart
+	suffix := ""
+	for i := 0; i < len(valuePart); i++ {
+		if valuePart[i] == '#' && (i == 0 || valuePart[i-1] == ' ' || valuePart[i-1] == '\t') {
+			value = valuePart[:i]
+			suffix = valuePart[i:]
+			break
+		}
+	}
+	trimmedValue := strings.TrimRight(value, " \t")
+	l.suffix = value[len(trimmedValue):] + suffix
+	l.value = trimmedValue
+	l.kind = envLineVariable
+	return rest
+}
+
+func closingQuote(text string, quote byte) int {
+	for i := 1; i < len(text); i++ {
+		if quote == '"' && text[i] == '\\' {
+			i++
+			continue
+		}
+		if text[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+func isEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r == '_' || r == '.' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func unquoteEnvValue(value string, quote byte) string {
+	if quote != '"' {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+			switch value[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(value[i])
+			}
+			continue
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
+
+// quoteEnvValue renders the value with the given quote.
+// Values that cannot be written unquoted or in single quotes are written in double quotes.
+func quoteEnvValue(value string, quote byte) string {
+	needsQuotes := value != strings.TrimSpace(value) ||
+		strings.ContainsAny(value, "#\"'\\\n\r\t") ||
+		strings.Contains(value, " ")
+	if quote == 0 && !needsQuotes {
+		return value
+	}
+	if quote == '\'' && !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(value) + `"`
+}
+
+// Keys returns the keys of all variables in the order of their first appearance.
+func (f *EnvFile) Keys() []string {
+	seen := make(map[string]struct{})
+	var keys []string
+	for _, l := range f.lines {
+		if l.kind != envLineVariable {
+			continue
+		}
+		if _, ok := seen[l.key]; ok {
+			continue
+		}
+		seen[l.key] = struct{}{}
+		keys = append(keys, l.key)
+	}
+	return keys
+}
+
+// Has returns true if the variable is defined in the document.
+func (f *EnvFile) Has(key string) bool {
+	return f.find(key) >= 0
+}
+
+// Get returns the unquoted value of the variable. If the variable is defined several times, the last value is returned.
+func (f *EnvFile) Get(key string) (string, bool) {
+	i := f.find(key)
+	if i < 0 {
+		return "", false
+	}
+	return f.lines[i].value, true
+}
+
+// Set updates the last definition of the variable keeping its quoting, export prefix and inline comment.
+// A new variable is appended to the end of the document.
+func (f *EnvFile) Set(key, value string) {
+	i := f.find(key)
+	if i < 0 {
+		f.ensureEndsWithNewline()
+		f.lines = append(f.lines, newEnvVariable(key, value))
+		return
+	}
+	l := f.lines[i]
+	if l.value == value {
+		return
+	}
+	l.value = value
+	l.modified = true
+}
+
+// Delete removes all definitions of the variable. Comments are kept.
+// It returns false if the variable is not found.
+func (f *EnvFile) Delete(key string) bool {
+	return f.DeleteWithComment(key, "")
+}
+
+// DeleteWithComment removes all definitions of the variable together with the comment line right above each of them
+// if the text of the comment equals the given one.
+func (f *EnvFile) DeleteWithComment(key, comment string) bool {
+	return f.deleteVariable(key, comment, func(*envLine) bool { return true })
+}
+
+// DeleteValueWithComment removes the definitions of the variable that have the given value
+// like DeleteWithComment does. Definitions with other values are kept.
+func (f *EnvFile) DeleteValueWithComment(key, value, comment string) bool {
+	return f.deleteVariable(key, comment, func(l *envLine) bool { return l.value == value })
+}
+
+func (f *EnvFile) deleteVariable(key, comment string, match func(l *envLine) bool) bool {
+	deleted := false
+	result := make([]*envLine, 0, len(f.lines))
+	for _, l := range f.lines {
+		if l.kind != envLineVariable || l.key != key || !match(l) {
+			result = append(result, l)
+			continue
+		}
+		deleted = true
+		if comment == "" || len(result) == 0 {
+			continue
+		}
+		prev := result[len(result)-1]
+		if prev.kind == envLineComment && commentText(prev.raw) == comment {
+			result = result[:len(result)-1]
+		}
+	}
+	f.lines = result
+	return deleted
+}
+
+// InsertAfterSection adds a new variable at the end of the section.
+// A section starts with a comment line with the section text and ends at a blank line or the end of the document.
+// If there is no such section, it is appended to the end of the document after a blank line.
+// If the variable already exists, its value is updated instead.
+// The comment is written above the variable if it is not empty.
+func (f *EnvFile) InsertAfterSection(section, key, value, comment string) {
+	if f.Has(key) {
+		f.Set(key, value)
+		return
+	}
+	added := make([]*envLine, 0, 2)
+	if comment != "" {
+		added = append(added, newEnvComment(comment))
+	}
+	added = append(added, newEnvVariable(key, value))
+
+	start := -1
+	for i, l := range f.lines {
+		if l.kind == envLineComment && commentText(l.raw) == section {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		f.AppendBlankLine()
+		f.ensureEndsWithNewline()
+		f.lines = append(f.lines, newEnvComment(section))
+		f.lines = append(f.lines, added...)
+		return
+	}
+	end := start + 1
+	for end < len(f.lines) && f.lines[end].kind != envLineBlank {
+		end++
+	}
+	if end == len(f.lines) {
+		f.ensureEndsWithNewline()
+	}
+	lines := make([]*envLine, 0, len(f.lines)+len(added))
+	lines = append(lines, f.lines[:end]...)
+	lines = append(lines, added...)
+	lines = append(lines, f.lines[end:]...)
+	f.lines = lines
+}
+
+// Append adds a new variable to the end of the document with the comment above it if the comment is not empty.
+// If the variable already exists, its value is updated instead.
+func (f *EnvFile) Append(key, value, comment string) {
+	if f.Has(key) {
+		f.Set(key, value)
+		return
+	}
+	f.ensureEndsWithNewline()
+	if comment != "" {
+		f.lines = append(f.lines, newEnvComment(comment))
+	}
+	f.lines = append(f.lines, newEnvVariable(key, value))
+}
+
+// AppendComment adds a comment line to the end of the document.
+func (f *EnvFile) AppendComment(comment string) {
+	f.ensureEndsWithNewline()
+	f.lines = append(f.lines, newEnvComment(comment))
+}
+
+// AppendBlankLine adds a blank line to the end of a non-empty document unless it already ends with a blank line.
+func (f *EnvFile) AppendBlankLine() {
+	if len(f.lines) == 0 || f.lines[len(f.lines)-1].kind == envLineBlank {
+		return
+	}
+	f.ensureEndsWithNewline()
+	f.lines = append(f.lines, &envLine{kind: envLineBlank, eol: "\n"})
+}
+
+// Bytes renders the document.
+func (f *EnvFile) Bytes() []byte {
+	var b strings.Builder
+	for _, l := range f.lines {
+		b.WriteString(l.render())
+		b.WriteString(l.eol)
+	}
+	return []byte(b.String())
+}
+
+// WriteFile writes the document to the file.
+func (f *EnvFile) WriteFile(path string) error {
+	return os.WriteFile(path, f.Bytes(), 0644)
+}
+
+func (f *EnvFile) find(key string) int {
+	for i := len(f.lines) - 1; i >= 0; i-- {
+		if f.lines[i].kind == envLineVariable && f.lines[i].key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *EnvFile) ensureEndsWithNewline() {
+	if len(f.lines) > 0 && f.lines[len(f.lines)-1].eol == "" {
+		f.lines[len(f.lines)-1].eol = "\n"
+	}
+}
+
+func newEnvVariable(key, value string) *envLine {
+	return &envLine{
+		kind:     envLineVariable,
+		eol:      "\n",
+		prefix:   key + "=",
+		key:      key,
+		value:    value,
+		modified: true,
+	}
+}
+
+func newEnvComment(comment string) *envLine {
+	return &envLine{
+		kind: envLineComment,
+		raw:  "# " + comment,
+		eol:  "\n",
+	}
+}
+
+func commentText(line string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+}
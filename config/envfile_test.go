@@ -0,0 +1,161 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-modulus/modulus/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnv(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"round trip keeps content unchanged", func(t *testing.T) {
+			t.Parallel()
+			contents := []string{
+				"",
+				"# App settings\nAPP_NAME=test\n\n# Database\nDB_DSN=postgres://localhost # local db\n",
+				"export KEY=value\n  SPACED = value \n",
+				"KEY=a#b\nQUOTED=\"a \\\"b\\\" # c\"\nSINGLE='a b'\n",
+				"MULTI=\"line 1\nline 2\"\nNEXT=1",
+				"CRLF=1\r\n# comment\r\n\r\nLAST=2\r\n",
+				"not a variable\nBROKEN=\"unterminated\n",
+			}
+			for _, content := range contents {
+				f := config.ParseEnv([]byte(content))
+
+				assert.Equal(t, content, string(f.Bytes()))
+			}
+		},
+	)
+
+	t.Run(
+		"values", func(t *testing.T) {
+			t.Parallel()
+			f := config.ParseEnv(
+				[]byte("KEY=a#b\nCOMMENTED=value # comment\nexport EXPORTED=1\nQUOTED=\"a\\nb\"\nMULTI='line 1\nline 2'\nKEY=c\n"),
+			)
+
+			assert.Equal(t, []string{"KEY", "COMMENTED", "EXPORTED", "QUOTED", "MULTI"}, f.Keys())
+			value, ok := f.Get("KEY")
+			assert.True(t, ok)
+			assert.Equal(t, "c", value)
+			value, _ = f.Get("COMMENTED")
+			assert.Equal(t, "value", value)
+			value, _ = f.Get("EXPORTED")
+			assert.Equal(t, "1", value)
+			value, _ = f.Get("QUOTED")
+			assert.Equal(t, "a\nb", value)
+			value, _ = f.Get("MULTI")
+			assert.Equal(t, "line 1\nline 2", value)
+			_, ok = f.Get("UNKNOWN")
+			assert.False(t, ok)
+		},
+	)
+}
+
+func TestEnvFile_Set(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"update keeps quoting, export and inline comment", func(t *testing.T) {
+			t.Parallel()
+			f := config.ParseEnv([]byte("# Settings\nexport A=1 # first\nB=\"old\"\nC='x'\n"))
+
+			f.Set("A", "2")
+			f.Set("B", "new value")
+			f.Set("C", "it's")
+
+			assert.Equal(t, "# Settings\nexport A=2 # first\nB=\"new value\"\nC=\"it's\"\n", string(f.Bytes()))
+		},
+	)
+
+	t.Run(
+		"append new variable", func(t *testing.T) {
+			t.Parallel()
+			f := config.ParseEnv([]byte("A=1"))
+
+			f.Set("B", "a b")
+			f.Append("C", "a#b", "Comment")
+
+			assert.Equal(t, "A=1\nB=\"a b\"\n# Comment\nC=\"a#b\"\n", string(f.Bytes()))
+		},
+	)
+}
+
+func TestEnvFile_Delete(t *testing.T) {
+	t.Parallel()
+	f := config.ParseEnv([]byte("# First\nA=1\n# Second\nB=2\n# Third\nC=3\n"))
+
+	assert.True(t, f.Delete("A"))
+	assert.True(t, f.DeleteWithComment("B", "Second"))
+	assert.False(t, f.Delete("UNKNOWN"))
+
+	assert.Equal(t, "# First\n# Third\nC=3\n", string(f.Bytes()))
+}
+
+func TestEnvFile_DeleteValueWithComment(t *testing.T) {
+	t.Parallel()
+	f := config.ParseEnv([]byte("# Level\nLEVEL=debug\nLEVEL=info\n# Level\nLEVEL=\"debug\"\n"))
+
+	assert.True(t, f.DeleteValueWithComment("LEVEL", "debug", "Level"))
+	assert.False(t, f.DeleteValueWithComment("LEVEL", "error", "Level"))
+
+	assert.Equal(t, "LEVEL=info\n", string(f.Bytes()))
+}
+
+func TestEnvFile_InsertAfterSection(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"existing section", func(t *testing.T) {
+			t.Parallel()
+			f := config.ParseEnv([]byte("# Database\nDB_HOST=localhost\n\n# Http\nHTTP_HOST=localhost\n"))
+
+			f.InsertAfterSection("Database", "DB_PORT", "5432", "Port")
+
+			assert.Equal(
+				t,
+				"# Database\nDB_HOST=localhost\n# Port\nDB_PORT=5432\n\n# Http\nHTTP_HOST=localhost\n",
+				string(f.Bytes()),
+			)
+		},
+	)
+
+	t.Run(
+		"missing section", func(t *testing.T) {
+			t.Parallel()
+			f := config.ParseEnv([]byte("A=1"))
+
+			f.InsertAfterSection("Database", "DB_PORT", "5432", "")
+
+			assert.Equal(t, "A=1\n\n# Database\nDB_PORT=5432\n", string(f.Bytes()))
+		},
+	)
+
+	t.Run(
+		"existing variable is updated in place", func(t *testing.T) {
+			t.Parallel()
+			f := config.ParseEnv([]byte("DB_PORT=1\n\n# Database\n"))
+
+			f.InsertAfterSection("Database", "DB_PORT", "5432", "")
+
+			assert.Equal(t, "DB_PORT=5432\n\n# Database\n", string(f.Bytes()))
+		},
+	)
+}
+
+func TestReadEnvFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), ".env")
+	f, err := config.ReadEnvFile(path)
+	require.NoError(t, err)
+
+	f.Set("KEY", "value")
+	require.NoError(t, f.WriteFile(path))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "KEY=value\n", string(content))
+}
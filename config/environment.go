@@ -0,0 +1,58 @@
+package config
+
+import "os"
+
+// Environment is the name of the environment the application is running in. It is set by the APP_ENV variable.
+// Custom environments are allowed: the .env.{APP_ENV} file is loaded for any value.
+type Environment string
+
+const (
+	EnvLocal   Environment = "local"
+	EnvDev     Environment = "dev"
+	EnvTest    Environment = "test"
+	EnvStaging Environment = "staging"
+	EnvProd    Environment = "prod"
+)
+
+// CurrentEnvironment returns the environment from the APP_ENV variable or EnvLocal if it is not set.
+func CurrentEnvironment() Environment {
+	env := os.Getenv("APP_ENV")
+	if env == "" {
+		return EnvLocal
+	}
+	return Environment(env)
+}
+
+// Is returns true if the environment is one of the given ones.
+func (e Environment) Is(envs ...Environment) bool {
+	for _, env := range envs {
+		if e == env {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Environment) IsLocal() bool {
+	return e == EnvLocal
+}
+
+func (e Environment) IsDev() bool {
+	return e == EnvDev
+}
+
+func (e Environment) IsTest() bool {
+	return e == EnvTest
+}
+
+func (e Environment) IsStaging() bool {
+	return e == EnvStaging
+}
+
+func (e Environment) IsProd() bool {
+	return e == EnvProd
+}
+
+func (e Environment) String() string {
+	return string(e)
+}
@@ -0,0 +1,25 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-modulus/modulus/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrentEnvironment(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	assert.Equal(t, config.EnvLocal, config.CurrentEnvironment())
+	assert.True(t, config.CurrentEnvironment().IsLocal())
+
+	t.Setenv("APP_ENV", "prod")
+	env := config.CurrentEnvironment()
+	assert.True(t, env.IsProd())
+	assert.True(t, config.IsProd())
+	assert.True(t, env.Is(config.EnvStaging, config.EnvProd))
+	assert.False(t, env.Is(config.EnvLocal, config.EnvDev))
+
+	t.Setenv("APP_ENV", "qa")
+	assert.Equal(t, config.Environment("qa"), config.CurrentEnvironment())
+	assert.False(t, config.IsProd())
+}
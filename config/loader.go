@@ -0,0 +1,198 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"braces.dev/errtrace"
+)
+
+var (
+	loaderMu   sync.Mutex
+	lastLoader *Loader
+)
+
+// Loader sets the env variables read by the configs of modules from layered sources.
+// The priority of the layers from the highest one:
+//   - overrides, e.g. the "--set KEY=VALUE" flags of the command line;
+//   - the process environment;
+//   - the .env chain: ".env.{APP_ENV}" and ".env";
+//   - config files (YAML, JSON or TOML);
+//   - the defaults of the env tags.
+//
+// Values of all layers are written to the process environment, so the configs are filled by the same env tags.
+type Loader struct {
+	overrides []Source
+	// envFiles is the .env chain. It is selected again by each Load, because APP_ENV and CONFIG_DIR may be overridden.
+	envFiles []Source
+	files    []Source
+}
+
+// NewLoader returns the loader of the .env chain used by LoadDefaultEnv
+// and the config files listed in the CONFIG_FILES variable separated by commas.
+// Add the "--set" flags of the command line with WithOverrides, e.g. like cli.LoadEnv does.
+func NewLoader() *Loader {
+	l := &Loader{
+		envFiles: envFileSources(DefaultEnvFiles()),
+	}
+	for _, path := range strings.Split(os.Getenv("CONFIG_FILES"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			l.files = append(l.files, NewFileSource(path))
+		}
+	}
+	return l
+}
+
+func envFileSources(paths []string) []Source {
+	sources := make([]Source, 0, len(paths))
+	for _, path := range paths {
+		sources = append(sources, NewEnvFileSource(path))
+	}
+	return sources
+}
+
+// WithFiles adds the config files. The format is chosen by the extension: .yaml, .yml, .json or .toml.
+// Files added later have priority over the ones added earlier.
+func (l *Loader) WithFiles(paths ...string) *Loader {
+	for _, path := range paths {
+		l.files = append([]Source{NewFileSource(path)}, l.files...)
+	}
+	return l
+}
+
+// WithOverrides adds sources that have priority over the process environment.
+// Sources added later have priority over the ones added earlier.
+func (l *Loader) WithOverrides(sources ...Source) *Loader {
+	for _, source := range sources {
+		l.overrides = append([]Source{source}, l.overrides...)
+	}
+	return l
+}
+
+// Files returns the paths of the .env and config files of the loader.
+func (l *Loader) Files() []string {
+	var files []string
+	for _, sources := range [][]Source{l.envFiles, l.files} {
+		for _, source := range sources {
+			if f, ok := source.(fileBased); ok {
+				files = append(files, f.path())
+			}
+		}
+	}
+	return files
+}
+
+// Load reads the sources and sets the env variables.
+// If it is called again, the variables set by the previous call are updated,
+// or unset if they are removed from the sources. Variables of the process environment are kept.
+// The loader is used by ReloadDefaultEnv afterward.
+func (l *Loader) Load() error {
+	overrides, err := readSources(l.overrides)
+	if err != nil {
+		return err
+	}
+	l.envFiles = envFileSources(
+		defaultEnvFiles(
+			func(key string) string {
+				if source, ok := overrides[key]; ok {
+					return source.value
+				}
+				return os.Getenv(key)
+			},
+		),
+	)
+	values, err := readSources(append(append([]Source{}, l.envFiles...), l.files...))
+	if err != nil {
+		return err
+	}
+
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	for key, source := range sources {
+		if source.loader != l {
+			continue
+		}
+		_, isOverride := overrides[key]
+		_, isValue := values[key]
+		if isOverride || isValue {
+			continue
+		}
+		if current, ok := os.LookupEnv(key); ok && current == source.value {
+			if err := os.Unsetenv(key); err != nil {
+				return errtrace.Wrap(err)
+			}
+		}
+		delete(sources, key)
+	}
+	for key, source := range values {
+		if _, ok := overrides[key]; ok || isProcessEnv(key) {
+			continue
+		}
+		if err := l.setEnv(key, source); err != nil {
+			return err
+		}
+	}
+	for key, source := range overrides {
+		if err := l.setEnv(key, source); err != nil {
+			return err
+		}
+	}
+
+	loaderMu.Lock()
+	lastLoader = l
+	loaderMu.Unlock()
+	return nil
+}
+
+// LoadedFiles returns the .env and config files of the last loaded Loader (the ones of LoadDefaultEnv by default).
+func LoadedFiles() []string {
+	loaderMu.Lock()
+	loader := lastLoader
+	loaderMu.Unlock()
+	if loader == nil {
+		return DefaultEnvFiles()
+	}
+	return loader.Files()
+}
+
+func (l *Loader) setEnv(key string, source envSource) error {
+	if err := os.Setenv(key, source.value); err != nil {
+		return errtrace.Wrap(err)
+	}
+	source.loader = l
+	sources[key] = source
+	return nil
+}
+
+// isProcessEnv returns true if the variable is set, but not by a source or it is changed after that.
+// sourcesMu must be locked.
+func isProcessEnv(key string) bool {
+	current, ok := os.LookupEnv(key)
+	if !ok {
+		return false
+	}
+	source, managed := sources[key]
+	return !managed || source.value != current
+}
+
+// readSources returns the values of the sources. The first source with the variable wins.
+func readSources(list []Source) (map[string]envSource, error) {
+	values := make(map[string]envSource)
+	for _, source := range list {
+		vars, err := source.Values()
+		if err != nil {
+			return nil, errtrace.Errorf("cannot read %s: %w", source.Name(), err)
+		}
+		for key, value := range vars {
+			if _, ok := values[key]; !ok {
+				values[key] = envSource{name: source.Name(), value: value}
+			}
+		}
+	}
+	return values, nil
+}
+
+type fileBased interface {
+	path() string
+}
@@ -0,0 +1,130 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-modulus/modulus/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Load(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONFIG_DIR", dir)
+	t.Setenv("APP_ENV", "layers")
+	t.Setenv("CONFIG_FILES", "")
+	require.NoError(
+		t,
+		os.WriteFile(
+			filepath.Join(dir, "config.yaml"),
+			[]byte("layers_test:\n  file: file\n  env: file\n  process: file\n  flag: file\n"),
+			0644,
+		),
+	)
+	require.NoError(
+		t,
+		os.WriteFile(
+			filepath.Join(dir, ".env"),
+			[]byte("LAYERS_TEST_ENV=env\nLAYERS_TEST_PROCESS=env\nLAYERS_TEST_FLAG=env\n"),
+			0644,
+		),
+	)
+	t.Setenv("LAYERS_TEST_PROCESS", "process")
+	t.Setenv("LAYERS_TEST_FLAG", "process")
+	for _, key := range []string{"LAYERS_TEST_FILE", "LAYERS_TEST_ENV"} {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+
+	loader := config.NewLoader().
+		WithFiles(filepath.Join(dir, "config.yaml")).
+		WithOverrides(config.NewArgsSource([]string{"serve", "--set", "LAYERS_TEST_FLAG=flag"}))
+	require.NoError(t, loader.Load())
+
+	t.Run(
+		"apply the layers by priority", func(t *testing.T) {
+			assert.Equal(t, "file", os.Getenv("LAYERS_TEST_FILE"))
+			assert.Equal(t, "env", os.Getenv("LAYERS_TEST_ENV"))
+			assert.Equal(t, "process", os.Getenv("LAYERS_TEST_PROCESS"))
+			assert.Equal(t, "flag", os.Getenv("LAYERS_TEST_FLAG"))
+		},
+	)
+
+	t.Run(
+		"report the sources", func(t *testing.T) {
+			assert.Equal(t, "config.yaml", config.EnvSource("LAYERS_TEST_FILE"))
+			assert.Equal(t, ".env", config.EnvSource("LAYERS_TEST_ENV"))
+			assert.Equal(t, config.EnvSourceProcess, config.EnvSource("LAYERS_TEST_PROCESS"))
+			assert.Equal(t, config.ArgsSourceName, config.EnvSource("LAYERS_TEST_FLAG"))
+		},
+	)
+
+	t.Run(
+		"return the watched files", func(t *testing.T) {
+			assert.Equal(
+				t,
+				[]string{dir + "/.env.layers", dir + "/.env", filepath.Join(dir, "config.yaml")},
+				config.LoadedFiles(),
+			)
+		},
+	)
+
+	t.Run(
+		"unset removed variables on reload", func(t *testing.T) {
+			require.NoError(
+				t,
+				os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("layers_test:\n  env: file\n"), 0644),
+			)
+
+			require.NoError(t, config.ReloadDefaultEnv())
+
+			_, ok := os.LookupEnv("LAYERS_TEST_FILE")
+			assert.False(t, ok)
+			assert.Equal(t, "env", os.Getenv("LAYERS_TEST_ENV"))
+			assert.Equal(t, "flag", os.Getenv("LAYERS_TEST_FLAG"))
+		},
+	)
+}
+
+func TestLoader_Load_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONFIG_DIR", dir)
+	t.Setenv("CONFIG_FILES", filepath.Join(dir, "config.ini"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.ini"), []byte("A=1"), 0644))
+
+	err := config.NewLoader().Load()
+
+	require.ErrorIs(t, err, config.ErrUnknownConfigFormat)
+}
+
+func TestLoader_Load_AppEnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONFIG_DIR", dir)
+	t.Setenv("CONFIG_FILES", "")
+	t.Setenv("APP_ENV", "")
+	t.Setenv("APP_ENV_TEST_LEVEL", "")
+	require.NoError(t, os.Unsetenv("APP_ENV"))
+	require.NoError(t, os.Unsetenv("APP_ENV_TEST_LEVEL"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("APP_ENV_TEST_LEVEL=default\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env.prod"), []byte("APP_ENV_TEST_LEVEL=prod\n"), 0644))
+
+	t.Log("Given APP_ENV is set by the --set flag")
+	loader := config.NewLoader().WithOverrides(config.NewArgsSource([]string{"serve", "--set", "APP_ENV=prod"}))
+	require.NoError(t, loader.Load())
+
+	t.Log("	Then the .env chain of the environment is loaded")
+	assert.Equal(t, "prod", os.Getenv("APP_ENV_TEST_LEVEL"))
+	assert.Equal(t, config.EnvProd, config.CurrentEnvironment())
+	assert.Equal(t, []string{dir + "/.env.prod", dir + "/.env"}, loader.Files())
+}
+
+func TestLoader_Load_InvalidSetFlag(t *testing.T) {
+	t.Setenv("CONFIG_DIR", t.TempDir())
+	t.Setenv("CONFIG_FILES", "")
+
+	err := config.NewLoader().WithOverrides(config.NewArgsSource([]string{"--set", "A"})).Load()
+
+	require.ErrorIs(t, err, config.ErrInvalidSetFlag)
+}
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/BurntSushi/toml"
+	"github.com/fatih/color"
+	"github.com/go-modulus/modulus/errors/errsys"
+	"github.com/subosito/gotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// ArgsSourceName is the name of the source of the "--set" flags reported by EnvSource.
+const ArgsSourceName = "--set"
+
+var (
+	ErrUnknownConfigFormat = errsys.New(
+		"unknown config format",
+		"Use a config file with the .yaml, .yml, .json or .toml extension",
+	)
+	ErrInvalidSetFlag = errsys.New(
+		"invalid set flag",
+		"The value of the --set flag must look like KEY=VALUE",
+	)
+)
+
+// Source is a layer of the config values for the Loader.
+// Keys of the values are the names of env variables used in the env tags of config structs.
+type Source interface {
+	// Name is reported by EnvSource as the source of the variables.
+	Name() string
+	Values() (map[string]string, error)
+}
+
+type mapSource struct {
+	name   string
+	values map[string]string
+}
+
+// NewMapSource returns the source of the given values.
+func NewMapSource(name string, values map[string]string) Source {
+	return &mapSource{name: name, values: values}
+}
+
+func (s *mapSource) Name() string {
+	return s.name
+}
+
+func (s *mapSource) Values() (map[string]string, error) {
+	return s.values, nil
+}
+
+type envFileSource struct {
+	filePath string
+}
+
+// NewEnvFileSource returns the source of the .env file. A missing file has no values.
+func NewEnvFileSource(path string) Source {
+	return &envFileSource{filePath: path}
+}
+
+func (s *envFileSource) Name() string {
+	return filepath.Base(s.filePath)
+}
+
+func (s *envFileSource) path() string {
+	return s.filePath
+}
+
+func (s *envFileSource) Values() (map[string]string, error) {
+	content, err := os.ReadFile(s.filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			debugLoaded(s.filePath, false)
+			return nil, nil
+		}
+		return nil, errtrace.Wrap(err)
+	}
+	env, err := gotenv.StrictParse(bytes.NewReader(content))
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	debugLoaded(s.filePath, true)
+	return env, nil
+}
+
+func debugLoaded(path string, success bool) {
+	if ok := os.Getenv("DEBUG"); ok == "" {
+		return
+	}
+	if success {
+		fmt.Println("Config is loaded from", color.BlueString(path))
+	} else {
+		fmt.Println("Config is not found ", color.RedString(path))
+	}
+}
+
+type fileSource struct {
+	filePath string
+}
+
+// NewFileSource returns the source of the YAML, JSON or TOML config file chosen by the extension.
+// Nested keys are joined with "_" and upper-cased: {"logger": {"level": "info"}} sets LOGGER_LEVEL=info.
+// Lists are joined with ",".
+func NewFileSource(path string) Source {
+	return &fileSource{filePath: path}
+}
+
+func (s *fileSource) Name() string {
+	return filepath.Base(s.filePath)
+}
+
+func (s *fileSource) path() string {
+	return s.filePath
+}
+
+func (s *fileSource) Values() (map[string]string, error) {
+	content, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	var data map[string]any
+	switch strings.ToLower(filepath.Ext(s.filePath)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &data)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		err = decoder.Decode(&data)
+	case ".toml":
+		err = toml.Unmarshal(content, &data)
+	default:
+		return nil, errtrace.Errorf("%s: %w", s.filePath, ErrUnknownConfigFormat)
+	}
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	debugLoaded(s.filePath, true)
+
+	values := make(map[string]string)
+	flattenConfig("", data, values)
+	return values, nil
+}
+
+func flattenConfig(prefix string, data map[string]any, values map[string]string) {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+		switch value := data[key].(type) {
+		case map[string]any:
+			flattenConfig(name, value, values)
+		case []any:
+			items := make([]string, 0, len(value))
+			for _, item := range value {
+				items = append(items, configValue(item))
+			}
+			values[name] = strings.Join(items, ",")
+		default:
+			values[name] = configValue(value)
+		}
+	}
+}
+
+func configValue(value any) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+type argsSource struct {
+	args []string
+}
+
+// NewArgsSource returns the source of the "--set KEY=VALUE" flags in the command line arguments.
+// Arguments after "--" are ignored.
+func NewArgsSource(args []string) Source {
+	return &argsSource{args: args}
+}
+
+func (s *argsSource) Name() string {
+	return ArgsSourceName
+}
+
+func (s *argsSource) Values() (map[string]string, error) {
+	values := make(map[string]string)
+	for i := 0; i < len(s.args); i++ {
+		arg := s.args[i]
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != "set" || !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(s.args) {
+				return nil, errtrace.Errorf("%s: %w", arg, ErrInvalidSetFlag)
+			}
+			i++
+			value = s.args[i]
+		}
+		key, val, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, errtrace.Errorf("%s: %w", value, ErrInvalidSetFlag)
+		}
+		values[strings.TrimSpace(key)] = val
+	}
+	return values, nil
+}
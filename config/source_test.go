@@ -0,0 +1,72 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-modulus/modulus/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFileSource(t *testing.T) {
+	t.Parallel()
+	files := map[string]string{
+		"config.yaml": "logger:\n  level: info\n  app-name: app\nhttp:\n  port: 8080\n  hosts:\n    - a\n    - b\n",
+		"config.json": `{"logger": {"level": "info", "app-name": "app"}, "http": {"port": 8080, "hosts": ["a", "b"]}}`,
+		"config.toml": "[logger]\nlevel = \"info\"\napp-name = \"app\"\n[http]\nport = 8080\nhosts = [\"a\", \"b\"]\n",
+	}
+	for name, content := range files {
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+				path := filepath.Join(t.TempDir(), name)
+				require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+				values, err := config.NewFileSource(path).Values()
+
+				require.NoError(t, err)
+				assert.Equal(
+					t, map[string]string{
+						"LOGGER_LEVEL":    "info",
+						"LOGGER_APP_NAME": "app",
+						"HTTP_PORT":       "8080",
+						"HTTP_HOSTS":      "a,b",
+					}, values,
+				)
+			},
+		)
+	}
+}
+
+func TestNewArgsSource(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"parse the set flags", func(t *testing.T) {
+			t.Parallel()
+			source := config.NewArgsSource(
+				[]string{
+					"serve", "--set", "A=1", "--set=B=2=3", "-set", "C=", "--verbose", "--", "--set", "D=4",
+				},
+			)
+
+			values, err := source.Values()
+
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{"A": "1", "B": "2=3", "C": ""}, values)
+			assert.Equal(t, config.ArgsSourceName, source.Name())
+		},
+	)
+
+	t.Run(
+		"fail on the invalid value", func(t *testing.T) {
+			t.Parallel()
+			for _, args := range [][]string{{"--set", "A"}, {"--set=B"}, {"--set"}} {
+				_, err := config.NewArgsSource(args).Values()
+
+				require.ErrorIs(t, err, config.ErrInvalidSetFlag, args)
+			}
+		},
+	)
+}
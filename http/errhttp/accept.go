@@ -0,0 +1,54 @@
+package errhttp
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AcceptedMediaTypes returns the media ranges of the Accept header from the most preferred one,
+// e.g. "application/*" goes after "application/json" for "application/*;q=0.8, application/json".
+// The ranges with q=0 are skipped.
+func AcceptedMediaTypes(accept string) []string {
+	type mediaRange struct {
+		mediaType string
+		quality   float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+		}
+	}
+	sort.SliceStable(
+		ranges, func(i, j int) bool {
+			return ranges[i].quality > ranges[j].quality
+		},
+	)
+	mediaTypes := make([]string, len(ranges))
+	for i, r := range ranges {
+		mediaTypes[i] = r.mediaType
+	}
+	return mediaTypes
+}
+
+// MatchesMediaRange checks if the media type matches the media range of the Accept header, e.g. "text/*".
+func MatchesMediaRange(mediaType, mediaRange string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(mediaRange, "/*")
+	return ok && strings.HasPrefix(mediaType, prefix+"/")
+}
@@ -0,0 +1,200 @@
+package errhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/go-modulus/modulus/errors"
+	"github.com/go-modulus/modulus/errors/errsys"
+	context2 "github.com/go-modulus/modulus/http/context"
+	"go.uber.org/fx"
+)
+
+const (
+	// EnvelopeMediaType is written by EnvelopeErrorWriter.
+	EnvelopeMediaType = "application/json"
+	// ProblemMediaType is written by ProblemErrorWriter.
+	ProblemMediaType = "application/problem+json"
+
+	ErrorFormatEnvelope = "envelope"
+	ErrorFormatProblem  = "problem"
+)
+
+var ErrUnknownErrorFormat = errsys.New(
+	"unknown error format",
+	"Set HTTP_ERROR_FORMAT to envelope, problem or the media type of an added error writer",
+)
+
+// ErrorWriter writes the error processed by the error pipeline to the response.
+type ErrorWriter interface {
+	// MediaType returns the media type of the written errors. The writer is selected by it from the Accept header.
+	MediaType() string
+	WriteError(w http.ResponseWriter, req *http.Request, err error)
+}
+
+type ErrorWriterConfig struct {
+	Format string `env:"HTTP_ERROR_FORMAT, default=envelope" comment:"Format of the error responses: envelope ({errors, data} like in GraphQL), problem (RFC 9457 application/problem+json) or the media type of an added error writer. The Accept header of a request can select another format"`
+	// ProblemTypeURL is the base of the problem type URIs, e.g. "https://example.com/problems/".
+	ProblemTypeURL string `env:"HTTP_PROBLEM_TYPE_URL" comment:"Base URL of the type of the problem details, the error code is appended to it. The type is about:blank if it is empty"`
+}
+
+// EnvelopeErrorWriter writes the errors like GraphQL: {"errors": [{"message", "extensions": {"code", "meta"}}], "data": null}.
+type EnvelopeErrorWriter struct{}
+
+func (EnvelopeErrorWriter) MediaType() string {
+	return EnvelopeMediaType
+}
+
+func (EnvelopeErrorWriter) WriteError(w http.ResponseWriter, _ *http.Request, err error) {
+	SendError(w, err)
+}
+
+// Problem is the problem details object of RFC 9457 with the extension members of modulus errors.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code is the code of the error, e.g. "post not found".
+	Code      string            `json:"code"`
+	RequestID string            `json:"requestId,omitempty"`
+	Meta      map[string]string `json:"meta,omitempty"`
+	// Errors are the invalid fields of a validation error.
+	Errors []ProblemField `json:"errors,omitempty"`
+}
+
+type ProblemField struct {
+	Field  string `json:"field"`
+	Detail string `json:"detail"`
+}
+
+// ProblemErrorWriter writes the errors as RFC 9457 problem details.
+type ProblemErrorWriter struct {
+	// TypeURL is the base of the problem type URIs. The type is "about:blank" if it is empty.
+	TypeURL string
+}
+
+func (ProblemErrorWriter) MediaType() string {
+	return ProblemMediaType
+}
+
+func (p ProblemErrorWriter) WriteError(w http.ResponseWriter, req *http.Request, err error) {
+	w.Header().Set("Content-Type", ProblemMediaType)
+	problem := p.Problem(req, err)
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// Problem maps the error to the problem details.
+// The hint becomes the detail, the fields of a validation error are listed in Errors instead of Meta.
+func (p ProblemErrorWriter) Problem(req *http.Request, err error) Problem {
+	status := HttpCode(err)
+	meta := errors.Meta(err)
+	delete(meta, HttpCodeMetaName)
+
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   errors.Hint(err),
+		Instance: req.URL.Path,
+		Code:     err.Error(),
+	}
+	if p.TypeURL != "" {
+		problem.Type = p.TypeURL + url.PathEscape(strings.ReplaceAll(problem.Code, " ", "-"))
+	}
+
+	problem.RequestID = meta["requestId"]
+	delete(meta, "requestId")
+	if problem.RequestID == "" {
+		problem.RequestID = context2.GetRequestID(req.Context())
+	}
+
+	if errors.HasTag(err, errors.ValidationErrorTag) {
+		for field, detail := range meta {
+			problem.Errors = append(problem.Errors, ProblemField{Field: field, Detail: detail})
+		}
+		sort.Slice(
+			problem.Errors, func(i, j int) bool {
+				return problem.Errors[i].Field < problem.Errors[j].Field
+			},
+		)
+		meta = nil
+	}
+	if len(meta) > 0 {
+		problem.Meta = meta
+	}
+	return problem
+}
+
+type ErrorWriterParams struct {
+	fx.In
+
+	Config ErrorWriterConfig
+	// Writers are added with the http.AddErrorWriter option of the http module.
+	Writers []ErrorWriter `group:"http.errorWriters"`
+}
+
+// NegotiatedErrorWriter selects the writer by the Accept header of the request.
+// The writer of the configured format is used if the Accept header doesn't name the media type of another writer.
+type NegotiatedErrorWriter struct {
+	defaultWriter ErrorWriter
+	writers       map[string]ErrorWriter
+}
+
+func NewDefaultErrorWriter(params ErrorWriterParams) (ErrorWriter, error) {
+	problem := ProblemErrorWriter{TypeURL: params.Config.ProblemTypeURL}
+	writers := map[string]ErrorWriter{
+		EnvelopeMediaType: EnvelopeErrorWriter{},
+		ProblemMediaType:  problem,
+	}
+	for _, writer := range params.Writers {
+		writers[writer.MediaType()] = writer
+	}
+
+	format := params.Config.Format
+	switch format {
+	case ErrorFormatEnvelope, "":
+		format = EnvelopeMediaType
+	case ErrorFormatProblem:
+		format = ProblemMediaType
+	}
+	defaultWriter, ok := writers[format]
+	if !ok {
+		return nil, errtrace.Wrap(errors.WithAddedMeta(ErrUnknownErrorFormat, "format", params.Config.Format))
+	}
+	return &NegotiatedErrorWriter{
+		defaultWriter: defaultWriter,
+		writers:       writers,
+	}, nil
+}
+
+func (n *NegotiatedErrorWriter) MediaType() string {
+	return n.defaultWriter.MediaType()
+}
+
+func (n *NegotiatedErrorWriter) WriteError(w http.ResponseWriter, req *http.Request, err error) {
+	n.Writer(req).WriteError(w, req, err)
+}
+
+// Writer returns the writer for the request.
+// The ranges of the Accept header matching the default writer, like */* or application/json for JSON based formats,
+// keep the default writer.
+func (n *NegotiatedErrorWriter) Writer(req *http.Request) ErrorWriter {
+	defaultMediaType := n.defaultWriter.MediaType()
+	for _, mediaRange := range AcceptedMediaTypes(req.Header.Get("Accept")) {
+		if MatchesMediaRange(defaultMediaType, mediaRange) ||
+			(mediaRange == EnvelopeMediaType && strings.HasSuffix(defaultMediaType, "+json")) {
+			return n.defaultWriter
+		}
+		if writer, ok := n.writers[mediaRange]; ok {
+			return writer
+		}
+	}
+	return n.defaultWriter
+}
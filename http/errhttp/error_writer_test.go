@@ -0,0 +1,159 @@
+package errhttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-modulus/modulus/errors"
+	"github.com/go-modulus/modulus/errors/errsys"
+	"github.com/go-modulus/modulus/errors/erruser"
+	context2 "github.com/go-modulus/modulus/http/context"
+	"github.com/go-modulus/modulus/http/errhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type textErrorWriter struct{}
+
+func (textErrorWriter) MediaType() string {
+	return "text/plain"
+}
+
+func (textErrorWriter) WriteError(w http.ResponseWriter, _ *http.Request, err error) {
+	w.WriteHeader(errhttp.HttpCode(err))
+	_, _ = w.Write([]byte(errors.Hint(err)))
+}
+
+func TestProblemErrorWriter_WriteError(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"error with meta", func(t *testing.T) {
+			t.Parallel()
+			err := errhttp.ErrWithHttpCode(
+				errors.WithMeta(erruser.New("post not found", "Post is not found"), "id", "42", "requestId", "abc"),
+				http.StatusNotFound,
+			)
+			writer := errhttp.ProblemErrorWriter{TypeURL: "https://example.com/problems/"}
+			rr := httptest.NewRecorder()
+
+			writer.WriteError(rr, httptest.NewRequest(http.MethodGet, "/posts/42?lang=en", nil), err)
+
+			t.Log("When the error is written as problem details")
+			t.Log("	Then the code, the hint, the meta and the request ID are mapped to the members")
+			require.Equal(t, http.StatusNotFound, rr.Code)
+			assert.Equal(t, errhttp.ProblemMediaType, rr.Header().Get("Content-Type"))
+			assert.JSONEq(
+				t, `{
+					"type": "https://example.com/problems/post-not-found",
+					"title": "Not Found",
+					"status": 404,
+					"detail": "Post is not found",
+					"instance": "/posts/42",
+					"code": "post not found",
+					"requestId": "abc",
+					"meta": {"id": "42"}
+				}`, rr.Body.String(),
+			)
+		},
+	)
+
+	t.Run(
+		"validation error", func(t *testing.T) {
+			t.Parallel()
+			err := erruser.NewValidationError(
+				erruser.New("title", "Required"),
+				erruser.New("content", "Too short"),
+			)
+			ctx := context2.WithRequestID(context.Background(), "xyz")
+			req := httptest.NewRequest(http.MethodPost, "/posts", nil).WithContext(ctx)
+			rr := httptest.NewRecorder()
+
+			errhttp.ProblemErrorWriter{}.WriteError(rr, req, err)
+
+			t.Log("When a validation error is written as problem details")
+			t.Log("	Then the invalid fields are listed in errors")
+			require.Equal(t, http.StatusBadRequest, rr.Code)
+			var problem errhttp.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+			assert.Equal(t, "about:blank", problem.Type)
+			assert.Equal(t, "invalid input", problem.Code)
+			assert.Equal(t, "xyz", problem.RequestID)
+			assert.Nil(t, problem.Meta)
+			assert.Equal(
+				t, []errhttp.ProblemField{
+					{Field: "content", Detail: "Too short"},
+					{Field: "title", Detail: "Required"},
+				}, problem.Errors,
+			)
+		},
+	)
+}
+
+func TestNewDefaultErrorWriter(t *testing.T) {
+	t.Parallel()
+	newWriter := func(t *testing.T, format string) *errhttp.NegotiatedErrorWriter {
+		writer, err := errhttp.NewDefaultErrorWriter(
+			errhttp.ErrorWriterParams{
+				Config:  errhttp.ErrorWriterConfig{Format: format},
+				Writers: []errhttp.ErrorWriter{textErrorWriter{}},
+			},
+		)
+		require.NoError(t, err)
+		return writer.(*errhttp.NegotiatedErrorWriter)
+	}
+	cases := []struct {
+		name     string
+		format   string
+		accept   string
+		expected string
+	}{
+		{"envelope by default", "envelope", "", errhttp.EnvelopeMediaType},
+		{"problem by default", "problem", "*/*", errhttp.ProblemMediaType},
+		{"json accepted by problem", "problem", "application/json", errhttp.ProblemMediaType},
+		{"problem by accept", "envelope", "application/problem+json", errhttp.ProblemMediaType},
+		{"preferred default", "envelope", "application/problem+json;q=0.5, application/json", errhttp.EnvelopeMediaType},
+		{"added writer by accept", "envelope", "text/plain", "text/plain"},
+		{"added writer by config", "text/plain", "text/html", "text/plain"},
+	}
+	for _, c := range cases {
+		t.Run(
+			c.name, func(t *testing.T) {
+				t.Parallel()
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				if c.accept != "" {
+					req.Header.Set("Accept", c.accept)
+				}
+
+				assert.Equal(t, c.expected, newWriter(t, c.format).Writer(req).MediaType())
+			},
+		)
+	}
+
+	t.Run(
+		"unknown format", func(t *testing.T) {
+			t.Parallel()
+			_, err := errhttp.NewDefaultErrorWriter(
+				errhttp.ErrorWriterParams{Config: errhttp.ErrorWriterConfig{Format: "yaml"}},
+			)
+
+			require.ErrorIs(t, err, errhttp.ErrUnknownErrorFormat)
+		},
+	)
+
+	t.Run(
+		"write error", func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", "text/plain")
+			rr := httptest.NewRecorder()
+
+			newWriter(t, "envelope").WriteError(rr, req, errsys.New("failed", "Something failed"))
+
+			assert.Equal(t, http.StatusInternalServerError, rr.Code)
+			assert.Equal(t, "Something failed", rr.Body.String())
+		},
+	)
+}
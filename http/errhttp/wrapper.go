@@ -64,7 +64,13 @@ func SendError(
 	)
 }
 
+// WrapHandler sends the errors of the handler processed by the pipeline with SendError.
 func WrapHandler(errorPipeline *ErrorPipeline, handler Handler) http.HandlerFunc {
+	return WrapHandlerWithWriter(errorPipeline, EnvelopeErrorWriter{}, handler)
+}
+
+// WrapHandlerWithWriter writes the errors of the handler processed by the pipeline with the writer.
+func WrapHandlerWithWriter(errorPipeline *ErrorPipeline, writer ErrorWriter, handler Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
 		defer func() {
@@ -79,14 +85,14 @@ func WrapHandler(errorPipeline *ErrorPipeline, handler Handler) http.HandlerFunc
 				}
 
 				err = errorPipeline.Process(ctx, err)
-				SendError(w, err)
+				writer.WriteError(w, req, err)
 			}
 		}()
 
 		err := handler(w, req)
 		if err != nil {
 			err = errorPipeline.Process(ctx, err)
-			SendError(w, err)
+			writer.WriteError(w, req, err)
 		}
 	}
 }
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"log/slog"
+	netHttp "net/http"
+
+	"github.com/go-modulus/modulus/http/errhttp"
+	"go.uber.org/fx"
+)
+
+type HandlerParams struct {
+	fx.In
+
+	Router        Router
+	Routes        []Route `group:"http.routes"`
+	Pipeline      *Pipeline
+	ErrorPipeline *errhttp.ErrorPipeline
+	ErrorWriter   errhttp.ErrorWriter
+	Encoders      *ResponseEncoders
+	Logger        *slog.Logger
+}
+
+// NewHandler registers the middlewares and the routes on the router the same way as the serve command does
+// and returns the router. It serves the requests without binding a port, e.g. in tests.
+// Note: the routes are registered on the router of the container, so don't call it in the app running the serve command.
+func NewHandler(params HandlerParams) netHttp.Handler {
+	var middlewares []Middleware
+	if params.Pipeline != nil {
+		middlewares = params.Pipeline.GetMiddlewares()
+	}
+	registerRoutes(
+		params.Router,
+		middlewares,
+		params.Routes,
+		params.ErrorPipeline,
+		params.ErrorWriter,
+		params.Encoders,
+		params.Logger.With(slog.String("component", "http")),
+	)
+	return params.Router
+}
+
+func registerRoutes(
+	router Router,
+	middlewares []Middleware,
+	routes []Route,
+	errorPipeline *errhttp.ErrorPipeline,
+	errorWriter errhttp.ErrorWriter,
+	encoders *ResponseEncoders,
+	logger *slog.Logger,
+) {
+	if len(middlewares) > 0 {
+		for _, middleware := range middlewares {
+			router.Use(middleware)
+		}
+
+		logger.Info("registering global middlewares", slog.Int("count", len(middlewares)))
+	}
+
+	count := 0
+	for _, route := range routes {
+		if route.IsEmpty() {
+			continue
+		}
+		logger.Debug(
+			"registering route",
+			slog.String("method", route.Method),
+			slog.String("path", route.Path),
+		)
+		router.Method(route.Method, route.Path, routeHandler(route, errorPipeline, errorWriter, encoders))
+		count++
+	}
+	logger.Info("registered routes", slog.Int("count", count))
+}
+
+// routeHandler returns the handler of the route wrapped by its middlewares.
+// Errors of ErrHandler are processed by the error pipeline of the route or by the global one
+// and written by the error writer. The responses of WriteResponse are written by the encoders.
+func routeHandler(
+	route Route,
+	errorPipeline *errhttp.ErrorPipeline,
+	errorWriter errhttp.ErrorWriter,
+	encoders *ResponseEncoders,
+) netHttp.Handler {
+	handler := route.Handler
+	if handler == nil {
+		if route.ErrorPipeline != nil {
+			errorPipeline = route.ErrorPipeline
+		}
+		handler = errhttp.WrapHandlerWithWriter(errorPipeline, errorWriter, route.ErrHandler)
+	}
+	if encoders != nil {
+		next := handler
+		handler = netHttp.HandlerFunc(
+			func(w netHttp.ResponseWriter, req *netHttp.Request) {
+				next.ServeHTTP(w, req.WithContext(withResponseEncoders(req.Context(), encoders)))
+			},
+		)
+	}
+	for i := len(route.Middlewares) - 1; i >= 0; i-- {
+		handler = route.Middlewares[i](handler)
+	}
+	return handler
+}
@@ -95,6 +95,21 @@ func WrapInputHandler[B any](handle InputHandler[B]) errhttp.Handler {
 	}
 }
 
+// TypedHandler returns the output written by WriteResponse instead of writing the response itself.
+type TypedHandler[I any, O any] func(req RequestWithInput[I]) (O, error)
+
+func WrapTypedHandler[I any, O any](handle TypedHandler[I, O]) errhttp.Handler {
+	return WrapInputHandler(
+		func(w http.ResponseWriter, req RequestWithInput[I]) error {
+			output, err := handle(req)
+			if err != nil {
+				return errtrace.Wrap(err)
+			}
+			return errtrace.Wrap(WriteResponse(w, req.Req(), output))
+		},
+	)
+}
+
 type OptionalJsonDecoder struct{}
 
 func (o *OptionalJsonDecoder) Decode(src io.Reader, dst interface{}) error {
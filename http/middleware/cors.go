@@ -1,9 +1,13 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"regexp"
+	"sync/atomic"
 
+	"github.com/go-modulus/modulus/errors/erruser"
+	"github.com/go-modulus/modulus/module"
 	"github.com/rs/cors"
 )
 
@@ -13,6 +17,42 @@ type CorsConfig struct {
 	MaxAge                   int      `env:"CORS_MAX_AGE, default=3600"`
 }
 
+// Validate checks that the host is a valid regular expression.
+func (c CorsConfig) Validate(context.Context) error {
+	if c.Host == "*" || c.Host == "" {
+		return nil
+	}
+	if _, err := regexp.Compile(c.Host); err != nil {
+		return erruser.NewValidationError(erruser.New("CORS_HOST", "Invalid regular expression"))
+	}
+	return nil
+}
+
+// ReloadableCors is the CORS middleware that follows the changes of the reloadable CorsConfig.
+type ReloadableCors struct {
+	cors atomic.Pointer[cors.Cors]
+}
+
+func NewReloadableCors(config *module.Reloadable[CorsConfig]) *ReloadableCors {
+	c := &ReloadableCors{}
+	c.cors.Store(NewCors(config.Get()))
+	config.Subscribe(
+		func(cfg CorsConfig) {
+			c.cors.Store(NewCors(cfg))
+		},
+	)
+	return c
+}
+
+// Handler applies the CORS rules of the current config.
+func (c *ReloadableCors) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			c.cors.Load().Handler(next).ServeHTTP(w, r)
+		},
+	)
+}
+
 func NewCors(config CorsConfig) *cors.Cors {
 	host := config.Host
 	if host == "*" || host == "" {
@@ -1,13 +1,16 @@
 package middleware_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/go-modulus/modulus/http/middleware"
+	"github.com/go-modulus/modulus/module"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
 )
 
 var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -194,3 +197,30 @@ func TestNewCors(t *testing.T) {
 		},
 	)
 }
+
+func TestReloadableCors(t *testing.T) {
+	t.Setenv("CORS_HOST", "^https://example.com$")
+	m := module.NewModule("cors").
+		AddProviders(middleware.NewReloadableCors).
+		WithOptions(module.InitReloadableConfig(middleware.CorsConfig{}))
+	var config *module.Reloadable[middleware.CorsConfig]
+	var c *middleware.ReloadableCors
+	app := fx.New(module.BuildFx(m), fx.NopLogger, fx.Populate(&config, &c))
+	require.NoError(t, app.Err())
+	handler := c.Handler(okHandler)
+
+	rr := corsRequest(t, handler, http.MethodGet, "https://other.com", nil)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+
+	t.Setenv("CORS_HOST", "^https://other.com$")
+	require.NoError(t, config.Reload(context.Background()))
+
+	rr = corsRequest(t, handler, http.MethodGet, "https://other.com", nil)
+	assert.Equal(t, "https://other.com", rr.Header().Get("Access-Control-Allow-Origin"))
+
+	t.Setenv("CORS_HOST", "^https://(invalid$")
+	require.Error(t, config.Reload(context.Background()))
+
+	rr = corsRequest(t, handler, http.MethodGet, "https://other.com", nil)
+	assert.Equal(t, "https://other.com", rr.Header().Get("Access-Control-Allow-Origin"))
+}
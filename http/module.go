@@ -9,6 +9,7 @@ import (
 	"github.com/go-modulus/modulus/http/middleware"
 	"github.com/go-modulus/modulus/logger"
 	"github.com/go-modulus/modulus/module"
+	"go.uber.org/fx"
 )
 
 var (
@@ -29,18 +30,26 @@ func NewModule(options ...module.Option) *module.Module {
 		).
 		AddCliCommands(
 			NewServeCommand,
+			NewOpenAPICommand,
 		).
 		AddProviders(
 			NewServe,
+			NewURLGenerator,
+			NewOpenAPIGenerator,
+			NewResponseEncoders,
+			middleware.NewReloadableCors,
 		).
-		SetOverriddenProvider("http.Router", NewDefaultRouter).
+		SetOverriddenProvider("http.Router", NewDefaultRouterWithErrorWriter).
 		SetOverriddenProvider("http.ErrorPipeline", errhttp.NewDefaultErrorPipeline).
+		SetOverriddenProvider("http.ErrorWriter", errhttp.NewDefaultErrorWriter).
 		SetOverriddenProvider(
 			"http.MiddlewarePipeline", NewDefaultPipeline,
 		).
 		InitConfig(ServeConfig{}).
-		InitConfig(middleware.CorsConfig{}).
 		InitConfig(errhttp.ErrorLoggerConfig{}).
+		InitConfig(errhttp.ErrorWriterConfig{}).
+		InitConfig(OpenAPIConfig{}).
+		WithOptions(module.InitReloadableConfig(middleware.CorsConfig{})).
 		WithOptions(options...)
 
 	return httpModule
@@ -61,6 +70,55 @@ func OverrideMiddlewarePipeline[T PipelineFactory](httpModule *module.Module) *m
 	return httpModule.SetOverriddenProvider("http.MiddlewarePipeline", func(impl T) *Pipeline { return impl.New() })
 }
 
+// OverrideErrorWriter replaces the writer of the error responses selecting the format by HTTP_ERROR_FORMAT
+// and the Accept header.
+func OverrideErrorWriter[T errhttp.ErrorWriter](httpModule *module.Module) *module.Module {
+	return httpModule.SetOverriddenProvider("http.ErrorWriter", func(impl T) errhttp.ErrorWriter { return impl })
+}
+
+// AddErrorWriter adds the error format to the default error writer.
+// It is used if HTTP_ERROR_FORMAT is set to its media type or the Accept header of the request names it.
+func AddErrorWriter(writer errhttp.ErrorWriter) module.Option {
+	return func(httpModule *module.Module) *module.Module {
+		return httpModule.AddProviders(
+			fx.Annotate(
+				func() errhttp.ErrorWriter { return writer },
+				fx.ResultTags(`group:"http.errorWriters"`),
+			),
+		)
+	}
+}
+
+// AddResponseEncoder adds the media type of the responses of TypedHandler, see WriteResponse.
+// It is used if the Accept header of the request prefers the media type of the encoder's content type.
+// Only JSON and XML encoders are shipped, e.g. MessagePack or CBOR need an encoder wrapping the codec of your choice:
+//
+//	http.NewModule(http.AddResponseEncoder(MsgpackEncoder{}))
+func AddResponseEncoder(encoder ResponseEncoder) module.Option {
+	return func(httpModule *module.Module) *module.Module {
+		return httpModule.AddProviders(
+			fx.Annotate(
+				func() ResponseEncoder { return encoder },
+				fx.ResultTags(`group:"http.responseEncoders"`),
+			),
+		)
+	}
+}
+
+// AddCorsToPipeline adds the CORS middleware to the pipeline. The default pipeline has no CORS middleware.
+// The allowed origins follow the changes of CORS_HOST without restarting the server if the app has
+// the module.WatchConfig option.
+func AddCorsToPipeline(rank int) module.Option {
+	return func(httpModule *module.Module) *module.Module {
+		return httpModule.AddInvokes(
+			func(pipeline *Pipeline, cors *middleware.ReloadableCors) *Pipeline {
+				pipeline.SetMiddleware(rank, cors.Handler)
+				return pipeline
+			},
+		)
+	}
+}
+
 func AddMiddlewareToPipeline(rank int, addedMiddleware Middleware) module.Option {
 	return func(httpModule *module.Module) *module.Module {
 		return httpModule.AddInvokes(
@@ -0,0 +1,547 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/go-modulus/modulus/http/errhttp"
+	"github.com/go-modulus/modulus/module"
+	"github.com/go-modulus/modulus/validator"
+	"github.com/urfave/cli/v3"
+	"go.uber.org/fx"
+)
+
+const OpenAPIVersion = "3.1.0"
+
+type OpenAPIConfig struct {
+	Title   string `env:"OPENAPI_TITLE, default=API" comment:"Title of the API in the generated OpenAPI spec"`
+	Version string `env:"OPENAPI_VERSION, default=1.0.0" comment:"Version of the API in the generated OpenAPI spec"`
+}
+
+type OpenAPI struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       OpenAPIInfo                `json:"info"`
+	Paths      map[string]OpenAPIPathItem `json:"paths"`
+	Components OpenAPIComponents          `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIPathItem contains the operations of a path by the lower-cased HTTP methods.
+type OpenAPIPathItem map[string]*OpenAPIOperation
+
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+}
+
+type OpenAPIParameter struct {
+	Name     string        `json:"name"`
+	In       string        `json:"in"`
+	Required bool          `json:"required,omitempty"`
+	Schema   OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIMediaType struct {
+	Schema OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIComponents struct {
+	Schemas map[string]OpenAPISchema `json:"schemas"`
+}
+
+// errorSchemaName is the name of the schema of the error envelope written by errhttp.SendError.
+const errorSchemaName = "Error"
+
+var openAPIMethods = map[string]struct{}{
+	http.MethodGet:     {},
+	http.MethodPut:     {},
+	http.MethodPost:    {},
+	http.MethodDelete:  {},
+	http.MethodOptions: {},
+	http.MethodHead:    {},
+	http.MethodPatch:   {},
+	http.MethodTrace:   {},
+}
+
+type OpenAPIGeneratorParams struct {
+	fx.In
+
+	Routes []Route `group:"http.routes"`
+	Config OpenAPIConfig
+	// Encoders are the media types of the responses of TypedHandler. The default encoders are used if it is nil.
+	Encoders *ResponseEncoders `optional:"true"`
+}
+
+// OpenAPIGenerator builds the OpenAPI spec of the registered routes.
+// Parameters and request bodies are described for the routes added by ProvideInputRoute and ProvideTypedRoute
+// from the `in` tags of their input structs, responses of ProvideTypedRoute are described from their output types.
+type OpenAPIGenerator struct {
+	routes   []Route
+	config   OpenAPIConfig
+	encoders *ResponseEncoders
+}
+
+func NewOpenAPIGenerator(params OpenAPIGeneratorParams) *OpenAPIGenerator {
+	encoders := params.Encoders
+	if encoders == nil {
+		encoders = defaultResponseEncoders
+	}
+	return &OpenAPIGenerator{
+		routes:   params.Routes,
+		config:   params.Config,
+		encoders: encoders,
+	}
+}
+
+func NewOpenAPICommand(g *OpenAPIGenerator) *cli.Command {
+	return &cli.Command{
+		Name:  "openapi",
+		Usage: "Print the OpenAPI spec of the http routes",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Path of the generated spec. Leave empty to print it",
+			},
+		},
+		Action: g.Invoke,
+	}
+}
+
+func (g *OpenAPIGenerator) Invoke(ctx context.Context, cmd *cli.Command) error {
+	spec, err := g.JSON()
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	path := cmd.String("output")
+	if path == "" {
+		_, err = cmd.Root().Writer.Write(spec)
+		return errtrace.Wrap(err)
+	}
+	if err := os.WriteFile(path, spec, 0644); err != nil {
+		return errtrace.Wrap(err)
+	}
+	fmt.Fprintln(cmd.Root().Writer, "Written", path)
+	return nil
+}
+
+// JSON returns the indented JSON of the spec.
+func (g *OpenAPIGenerator) JSON() ([]byte, error) {
+	spec, err := json.MarshalIndent(g.Spec(), "", "  ")
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	return append(spec, '\n'), nil
+}
+
+// ServeHTTP writes the spec as JSON.
+func (g *OpenAPIGenerator) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	spec, err := g.JSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write(spec)
+}
+
+// Spec builds the spec of the routes. Routes with methods unsupported by OpenAPI are skipped.
+func (g *OpenAPIGenerator) Spec() *OpenAPI {
+	schemas := newSchemaBuilder()
+	schemas.schemas[errorSchemaName] = errorEnvelopeSchema()
+	spec := &OpenAPI{
+		OpenAPI: OpenAPIVersion,
+		Info: OpenAPIInfo{
+			Title:   g.config.Title,
+			Version: g.config.Version,
+		},
+		Paths:      make(map[string]OpenAPIPathItem),
+		Components: OpenAPIComponents{Schemas: schemas.schemas},
+	}
+
+	// the same name can be used for several methods of one path, but the operation IDs must be unique
+	names := make(map[string]int)
+	for _, route := range g.routes {
+		if route.Name != "" {
+			names[route.Name]++
+		}
+	}
+
+	for _, route := range g.routes {
+		method := strings.ToUpper(route.Method)
+		if _, ok := openAPIMethods[method]; !ok || route.IsEmpty() {
+			continue
+		}
+		path, wildcards := openAPIPath(route.Path)
+		operation := &OpenAPIOperation{
+			OperationID: route.Name,
+			Responses: map[string]OpenAPIResponse{
+				"200":     {Description: "Successful response"},
+				"default": errorResponse("Error", schemas),
+			},
+		}
+		if names[route.Name] > 1 {
+			operation.OperationID = route.Name + "_" + strings.ToLower(method)
+		}
+		if route.Output != nil {
+			delete(operation.Responses, "200")
+			status, response := outputResponse(route.Output, g.encoders.MediaTypes(), schemas)
+			operation.Responses[status] = response
+		}
+		// struct{} is used by the typed handlers without input
+		if route.Input != nil && route.Input != reflect.TypeFor[struct{}]() {
+			addInput(operation, route.Input, schemas)
+			operation.Responses["400"] = errorResponse(inputErrorDescription(route.Input), schemas)
+		}
+		addPathParameters(operation, wildcards)
+
+		item, ok := spec.Paths[path]
+		if !ok {
+			item = make(OpenAPIPathItem)
+			spec.Paths[path] = item
+		}
+		item[strings.ToLower(method)] = operation
+	}
+	return spec
+}
+
+// AddOpenAPIRoute adds the GET route returning the OpenAPI spec of all routes, e.g. "/openapi.json".
+func AddOpenAPIRoute(path string) module.Option {
+	return func(httpModule *module.Module) *module.Module {
+		// the generator depends on all routes, so the route gets it after the container is built
+		handler := &openAPIHandler{}
+		return httpModule.
+			AddProviders(
+				func() RouteProvider {
+					return ProvideRawRoute(http.MethodGet, path, handler)
+				},
+			).
+			AddInvokes(
+				func(g *OpenAPIGenerator) {
+					handler.generator = g
+				},
+			)
+	}
+}
+
+type openAPIHandler struct {
+	generator *OpenAPIGenerator
+}
+
+func (h *openAPIHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.generator.ServeHTTP(w, req)
+}
+
+// openAPIPath converts the net/http.ServeMux pattern to the OpenAPI path and returns the names of its wildcards.
+func openAPIPath(pattern string) (string, []string) {
+	var wildcards []string
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		wildcard := strings.TrimSuffix(segment[1:len(segment)-1], "...")
+		if wildcard == "$" {
+			segments[i] = ""
+			continue
+		}
+		wildcards = append(wildcards, wildcard)
+		segments[i] = "{" + wildcard + "}"
+	}
+	return strings.Join(segments, "/"), wildcards
+}
+
+// addPathParameters adds the wildcards of the path that are not described by the input.
+func addPathParameters(operation *OpenAPIOperation, wildcards []string) {
+	for _, wildcard := range wildcards {
+		described := false
+		for _, param := range operation.Parameters {
+			if param.In == "path" && param.Name == wildcard {
+				described = true
+				break
+			}
+		}
+		if !described {
+			operation.Parameters = append(
+				operation.Parameters, OpenAPIParameter{
+					Name:     wildcard,
+					In:       "path",
+					Required: true,
+					Schema:   OpenAPISchema{"type": "string"},
+				},
+			)
+		}
+	}
+}
+
+// inDirective is a directive of the `in` tag of httpin, e.g. "query=page,p" or "required".
+type inDirective struct {
+	name string
+	args []string
+}
+
+func parseInTag(tag string) []inDirective {
+	var directives []inDirective
+	for _, part := range strings.Split(tag, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, args, _ := strings.Cut(part, "=")
+		directive := inDirective{name: strings.TrimSpace(name)}
+		if args != "" {
+			for _, arg := range strings.Split(args, ",") {
+				directive.args = append(directive.args, strings.TrimSpace(arg))
+			}
+		}
+		directives = append(directives, directive)
+	}
+	return directives
+}
+
+// addInput adds the parameters and the request body of the input struct decoded by httpin.
+func addInput(operation *OpenAPIOperation, input reflect.Type, schemas *schemaBuilder) {
+	form := OpenAPISchema{"type": "object", "properties": map[string]OpenAPISchema{}}
+	var formRequired []string
+	multipart := false
+	addInputFields(operation, input, schemas, form, &formRequired, &multipart)
+
+	properties := form["properties"].(map[string]OpenAPISchema)
+	if len(properties) == 0 || operation.RequestBody != nil {
+		return
+	}
+	if len(formRequired) > 0 {
+		form["required"] = formRequired
+	}
+	mediaType := "application/x-www-form-urlencoded"
+	if multipart {
+		mediaType = "multipart/form-data"
+	}
+	operation.RequestBody = &OpenAPIRequestBody{
+		Required: len(formRequired) > 0,
+		Content:  map[string]OpenAPIMediaType{mediaType: {Schema: form}},
+	}
+}
+
+func addInputFields(
+	operation *OpenAPIOperation,
+	input reflect.Type,
+	schemas *schemaBuilder,
+	form OpenAPISchema,
+	formRequired *[]string,
+	multipart *bool,
+) {
+	for input.Kind() == reflect.Pointer {
+		input = input.Elem()
+	}
+	if input.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < input.NumField(); i++ {
+		field := input.Field(i)
+		tag, ok := field.Tag.Lookup("in")
+		if !ok {
+			// httpin decodes the fields of the nested structs
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if field.IsExported() && fieldType.Kind() == reflect.Struct && fieldType != timeType {
+				addInputFields(operation, fieldType, schemas, form, formRequired, multipart)
+			}
+			continue
+		}
+
+		directives := parseInTag(tag)
+		required := false
+		var defaultValue string
+		for _, directive := range directives {
+			switch directive.name {
+			case "required", "nonzero":
+				required = true
+			case "default":
+				defaultValue = strings.Join(directive.args, ",")
+			}
+		}
+		schema := func() OpenAPISchema {
+			schema := schemas.schema(field.Type)
+			if defaultValue != "" {
+				schema["default"] = defaultSchemaValue(defaultValue)
+			}
+			return schema
+		}
+
+		for _, directive := range directives {
+			switch directive.name {
+			case "query", "header", "path":
+				if len(directive.args) == 0 {
+					continue
+				}
+				operation.Parameters = append(
+					operation.Parameters, OpenAPIParameter{
+						Name:     directive.args[0],
+						In:       directive.name,
+						Required: required || directive.name == "path",
+						Schema:   schema(),
+					},
+				)
+			case "form":
+				if len(directive.args) == 0 {
+					continue
+				}
+				form["properties"].(map[string]OpenAPISchema)[directive.args[0]] = schema()
+				if required {
+					*formRequired = append(*formRequired, directive.args[0])
+				}
+				if isFile(field.Type) {
+					*multipart = true
+				}
+			case "body":
+				bodyType := field.Type
+				for bodyType.Kind() == reflect.Pointer {
+					bodyType = bodyType.Elem()
+				}
+				operation.RequestBody = requestBody(directive.args, schemas.schema(bodyType))
+			}
+		}
+	}
+}
+
+// isFile checks if the field is a file uploaded by a multipart form, e.g. *httpin.File or []*httpin.File.
+func isFile(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t == fileType
+}
+
+// defaultSchemaValue returns the value of the default directive as a JSON value, e.g. 10 or true, or as a string.
+func defaultSchemaValue(value string) any {
+	var decoded any
+	if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+		return decoded
+	}
+	return value
+}
+
+// requestBody describes the body decoded by the format of the body directive: json (the default), optionalJson or xml.
+func requestBody(args []string, schema OpenAPISchema) *OpenAPIRequestBody {
+	format := "json"
+	if len(args) > 0 && args[0] != "" {
+		format = args[0]
+	}
+	mediaType := "application/json"
+	if strings.EqualFold(format, "xml") {
+		mediaType = "application/xml"
+	}
+	return &OpenAPIRequestBody{
+		Required: format != "optionalJson",
+		Content:  map[string]OpenAPIMediaType{mediaType: {Schema: schema}},
+	}
+}
+
+// outputResponse describes the output of TypedHandler in all media types of the response encoders.
+func outputResponse(output reflect.Type, mediaTypes []string, schemas *schemaBuilder) (string, OpenAPIResponse) {
+	status := outputStatus(output)
+	schema := schemas.schema(output)
+	content := make(map[string]OpenAPIMediaType)
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = OpenAPIMediaType{Schema: schema}
+	}
+	return strconv.Itoa(status), OpenAPIResponse{
+		Description: http.StatusText(status),
+		Content:     content,
+	}
+}
+
+// outputStatus returns the status of the zero value of the output if it implements StatusCoder by a value receiver.
+// The status is 200 if the output is a pointer or an interface, the zero value returns 0 or StatusCode panics on it.
+func outputStatus(output reflect.Type) (status int) {
+	status = http.StatusOK
+	if output.Kind() == reflect.Pointer || output.Kind() == reflect.Interface ||
+		!output.Implements(reflect.TypeFor[StatusCoder]()) {
+		return status
+	}
+	defer func() {
+		if recover() != nil {
+			status = http.StatusOK
+		}
+	}()
+	if code := reflect.Zero(output).Interface().(StatusCoder).StatusCode(); code != 0 {
+		status = code
+	}
+	return status
+}
+
+func inputErrorDescription(input reflect.Type) string {
+	if implements(input, reflect.TypeFor[validator.Validatable]()) {
+		return "The input cannot be decoded or is not valid"
+	}
+	return "The input cannot be decoded"
+}
+
+// errorResponse describes the formats of the errors written by the default error writer.
+func errorResponse(description string, schemas *schemaBuilder) OpenAPIResponse {
+	return OpenAPIResponse{
+		Description: description,
+		Content: map[string]OpenAPIMediaType{
+			errhttp.EnvelopeMediaType: {Schema: OpenAPISchema{"$ref": "#/components/schemas/" + errorSchemaName}},
+			errhttp.ProblemMediaType:  {Schema: schemas.schema(reflect.TypeFor[errhttp.Problem]())},
+		},
+	}
+}
+
+// errorEnvelopeSchema describes the JSON written by errhttp.SendError.
+func errorEnvelopeSchema() OpenAPISchema {
+	return OpenAPISchema{
+		"type":     "object",
+		"required": []string{"errors", "data"},
+		"properties": map[string]OpenAPISchema{
+			"data": {"type": "null"},
+			"errors": {
+				"type": "array",
+				"items": OpenAPISchema{
+					"type":     "object",
+					"required": []string{"message", "extensions"},
+					"properties": map[string]OpenAPISchema{
+						"message": {"type": "string"},
+						"extensions": {
+							"type":     "object",
+							"required": []string{"code"},
+							"properties": map[string]OpenAPISchema{
+								"code": {"type": "string"},
+								"meta": {
+									"type":                 []string{"object", "null"},
+									"additionalProperties": OpenAPISchema{"type": "string"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
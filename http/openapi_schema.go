@@ -0,0 +1,186 @@
+package http
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	httpinCore "github.com/ggicci/httpin/core"
+)
+
+// OpenAPISchema is a JSON Schema (draft 2020-12) used by OpenAPI 3.1.
+type OpenAPISchema map[string]any
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	fileType          = reflect.TypeFor[httpinCore.File]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// schemaBuilder builds the schemas of Go types as encoding/json writes them.
+// Named structs are added to the components once and referenced by $ref.
+type schemaBuilder struct {
+	schemas map[string]OpenAPISchema
+	names   map[reflect.Type]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		schemas: make(map[string]OpenAPISchema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) OpenAPISchema {
+	if t.Kind() != reflect.Pointer {
+		return b.valueSchema(t)
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return nullable(b.valueSchema(t))
+}
+
+func (b *schemaBuilder) valueSchema(t reflect.Type) OpenAPISchema {
+	switch {
+	case t == timeType:
+		return OpenAPISchema{"type": "string", "format": "date-time"}
+	case t == fileType:
+		return OpenAPISchema{"type": "string", "format": "binary"}
+	case implements(t, jsonMarshalerType):
+		// the format is unknown
+		return OpenAPISchema{}
+	case implements(t, textMarshalerType):
+		return OpenAPISchema{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return OpenAPISchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return OpenAPISchema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return OpenAPISchema{"type": "number"}
+	case reflect.String:
+		return OpenAPISchema{"type": "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return OpenAPISchema{"type": "string", "format": "byte"}
+		}
+		// nil slices are written as null
+		return OpenAPISchema{"type": []string{"array", "null"}, "items": b.schema(t.Elem())}
+	case reflect.Array:
+		return OpenAPISchema{
+			"type":     "array",
+			"items":    b.schema(t.Elem()),
+			"minItems": t.Len(),
+			"maxItems": t.Len(),
+		}
+	case reflect.Map:
+		return OpenAPISchema{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.objectSchema(t)
+		}
+		return OpenAPISchema{"$ref": b.ref(t)}
+	default:
+		return OpenAPISchema{}
+	}
+}
+
+// ref adds the schema of the named struct to the components and returns the reference to it.
+func (b *schemaBuilder) ref(t reflect.Type) string {
+	name, ok := b.names[t]
+	if !ok {
+		name = b.uniqueName(t)
+		b.names[t] = name
+		// the name is reserved before building to support recursive types
+		b.schemas[name] = nil
+		b.schemas[name] = b.objectSchema(t)
+	}
+	return "#/components/schemas/" + name
+}
+
+func (b *schemaBuilder) uniqueName(t reflect.Type) string {
+	base := strings.Map(
+		func(r rune) rune {
+			switch r {
+			case '[', ']', ',', '*', '/', ' ':
+				return '_'
+			}
+			return r
+		}, t.Name(),
+	)
+	name := base
+	for i := 2; ; i++ {
+		if _, ok := b.schemas[name]; !ok {
+			return name
+		}
+		name = base + strconv.Itoa(i)
+	}
+}
+
+func (b *schemaBuilder) objectSchema(t reflect.Type) OpenAPISchema {
+	properties := make(map[string]OpenAPISchema)
+	var required []string
+	b.addProperties(t, properties, &required)
+	schema := OpenAPISchema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addProperties adds the fields of the struct as encoding/json writes them.
+// Fields without omitempty are always written, so they are required.
+func (b *schemaBuilder) addProperties(t reflect.Type, properties map[string]OpenAPISchema, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			b.addProperties(fieldType, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = b.schema(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func implements(t reflect.Type, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// nullable allows null in addition to the values of the schema.
+func nullable(schema OpenAPISchema) OpenAPISchema {
+	switch schemaType := schema["type"].(type) {
+	case string:
+		schema["type"] = []string{schemaType, "null"}
+		return schema
+	case []string:
+		return schema
+	}
+	if len(schema) == 0 {
+		return schema
+	}
+	return OpenAPISchema{"anyOf": []OpenAPISchema{schema, {"type": "null"}}}
+}
@@ -0,0 +1,143 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	netHttp "net/http"
+	"testing"
+	"time"
+
+	"github.com/go-modulus/modulus/http"
+	"github.com/go-modulus/modulus/test"
+	"github.com/go-modulus/modulus/test/testhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type openAPIAuthor struct {
+	Name string `json:"name"`
+}
+
+type openAPIPost struct {
+	Title       string         `json:"title"`
+	Tags        []string       `json:"tags,omitempty"`
+	PublishedAt *time.Time     `json:"publishedAt"`
+	Author      openAPIAuthor  `json:"author"`
+	Related     []*openAPIPost `json:"related,omitempty"`
+}
+
+type updatePostInput struct {
+	ID     string       `in:"path=id"`
+	Lang   string       `in:"query=lang;default=en"`
+	Limit  int          `in:"query=limit;default=10"`
+	Token  string       `in:"header=X-Token;required"`
+	Post   *openAPIPost `in:"body=json"`
+	secret string
+}
+
+func (i *updatePostInput) Validate(ctx context.Context) error {
+	return nil
+}
+
+type searchInput struct {
+	Pagination struct {
+		Page int `in:"query=page"`
+	}
+	Filter *openAPIAuthor `in:"body=optionalJson"`
+}
+
+func TestOpenAPIGenerator_Spec(t *testing.T) {
+	t.Parallel()
+	mod := http.NewModule().AddProviders(
+		func() http.RouteProvider {
+			return http.ProvideInputRoute(
+				netHttp.MethodPut, "/posts/{id}", func(w netHttp.ResponseWriter, req http.RequestWithInput[updatePostInput]) error {
+					return nil
+				},
+			).Named("post")
+		},
+		func() http.RouteProvider {
+			return http.ProvideRoute(netHttp.MethodDelete, "/posts/{id}", fail).Named("post")
+		},
+		func() http.RoutesProvider {
+			return http.ProvideRouteGroup(
+				http.NewRouteGroup("/files").
+					Handle(netHttp.MethodGet, "/{$}", fail).
+					Handle(netHttp.MethodGet, "/{bucket}/{path...}", fail).Name("file"),
+			)
+		},
+		func() http.RouteProvider {
+			return http.ProvideInputRoute(
+				netHttp.MethodPost, "/search", func(w netHttp.ResponseWriter, req http.RequestWithInput[searchInput]) error {
+					return nil
+				},
+			)
+		},
+		func() http.RouteProvider {
+			return http.ProvideTypedRoute(
+				netHttp.MethodPost, "/authors", func(req http.RequestWithInput[struct{}]) (createdPost, error) {
+					return createdPost{}, nil
+				},
+			)
+		},
+	)
+	generator := test.Get[*http.OpenAPIGenerator](test.NewHarness(t, mod))
+
+	spec, err := generator.JSON()
+
+	require.NoError(t, err)
+	test.MatchJSONSnapshot(t, "", spec)
+}
+
+type statusPost struct {
+	Status int `json:"-"`
+}
+
+func (p statusPost) StatusCode() int {
+	return p.Status
+}
+
+func TestOpenAPIGenerator_Spec_OutputStatus(t *testing.T) {
+	t.Parallel()
+	generator := http.NewOpenAPIGenerator(
+		http.OpenAPIGeneratorParams{
+			Routes: []http.Route{
+				http.ProvideTypedRoute(
+					netHttp.MethodGet, "/posts", func(req http.RequestWithInput[struct{}]) (statusPost, error) {
+						return statusPost{Status: netHttp.StatusAccepted}, nil
+					},
+				).Route,
+			},
+			Encoders: http.NewResponseEncoders(http.ResponseEncodersParams{Encoders: []http.ResponseEncoder{textEncoder{}}}),
+		},
+	)
+
+	t.Log("When the zero value of the output returns the status 0")
+	spec := generator.Spec()
+
+	t.Log("	Then the response is documented with the status 200 in the media types of the encoders")
+	response, ok := spec.Paths["/posts"]["get"].Responses["200"]
+	require.True(t, ok)
+	assert.Contains(t, response.Content, "text/plain")
+	assert.Contains(t, response.Content, "application/json")
+}
+
+func TestAddOpenAPIRoute(t *testing.T) {
+	t.Parallel()
+	mod := http.NewModule(http.AddOpenAPIRoute("/openapi.json")).AddProviders(
+		func() http.RouteProvider {
+			return http.ProvideRoute(netHttp.MethodGet, "/posts", fail)
+		},
+	)
+	client := testhttp.NewClient(t, test.NewHarness(t, mod))
+
+	resp := client.Get("/openapi.json").
+		ExpectStatus(netHttp.StatusOK).
+		ExpectHeader("Content-Type", "application/json; charset=utf-8")
+
+	var spec http.OpenAPI
+	require.NoError(t, json.Unmarshal(resp.Body, &spec))
+	assert.Equal(t, http.OpenAPIVersion, spec.OpenAPI)
+	assert.Contains(t, spec.Paths, "/posts")
+	assert.Contains(t, spec.Paths, "/openapi.json")
+}
@@ -0,0 +1,186 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/go-modulus/modulus/errors/erruser"
+	"github.com/go-modulus/modulus/http/errhttp"
+	"go.uber.org/fx"
+)
+
+var ErrNotAcceptable = errhttp.ErrWithHttpCode(
+	erruser.New("not acceptable", "The response cannot be encoded to the media types of the Accept header"),
+	http.StatusNotAcceptable,
+)
+
+// ResponseEncoder writes the output of TypedHandler in a media type.
+type ResponseEncoder interface {
+	// ContentType returns the value of the Content-Type header, e.g. "application/json; charset=utf-8".
+	ContentType() string
+	Encode(w io.Writer, v any) error
+}
+
+// StatusCoder sets the status code of the response of TypedHandler. The status is 200 by default.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+type JSONEncoder struct{}
+
+func (JSONEncoder) ContentType() string {
+	return "application/json; charset=utf-8"
+}
+
+func (JSONEncoder) Encode(w io.Writer, v any) error {
+	return errtrace.Wrap(json.NewEncoder(w).Encode(v))
+}
+
+// XMLEncoder encodes the responses as XML.
+type XMLEncoder struct {
+	// MediaType is the media type of the Content-Type header, "application/xml" if it is empty.
+	MediaType string
+}
+
+func (e XMLEncoder) ContentType() string {
+	mediaType := e.MediaType
+	if mediaType == "" {
+		mediaType = "application/xml"
+	}
+	return mediaType + "; charset=utf-8"
+}
+
+func (XMLEncoder) Encode(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errtrace.Wrap(err)
+	}
+	return errtrace.Wrap(xml.NewEncoder(w).Encode(v))
+}
+
+const defaultMediaType = "application/json"
+
+type ResponseEncodersParams struct {
+	fx.In
+
+	// Encoders are added with the http.AddResponseEncoder option of the http module.
+	Encoders []ResponseEncoder `group:"http.responseEncoders"`
+}
+
+// ResponseEncoders selects the encoder of the output of TypedHandler by the Accept header of the request.
+// JSON and XML are supported by default, JSON is used if the request has no Accept header.
+type ResponseEncoders struct {
+	encoders   map[string]ResponseEncoder
+	mediaTypes []string
+}
+
+// NewResponseEncoders returns the default encoders with the added ones.
+// An added encoder is selected by the media type of its content type and replaces the default one of the same type.
+func NewResponseEncoders(params ResponseEncodersParams) *ResponseEncoders {
+	encoders := map[string]ResponseEncoder{
+		defaultMediaType:  JSONEncoder{},
+		"application/xml": XMLEncoder{},
+		"text/xml":        XMLEncoder{MediaType: "text/xml"},
+	}
+	for _, encoder := range params.Encoders {
+		encoders[encoderMediaType(encoder)] = encoder
+	}
+	mediaTypes := make([]string, 0, len(encoders))
+	for mediaType := range encoders {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Slice(
+		mediaTypes, func(i, j int) bool {
+			if mediaTypes[i] == defaultMediaType || mediaTypes[j] == defaultMediaType {
+				return mediaTypes[i] == defaultMediaType
+			}
+			return mediaTypes[i] < mediaTypes[j]
+		},
+	)
+	return &ResponseEncoders{
+		encoders:   encoders,
+		mediaTypes: mediaTypes,
+	}
+}
+
+// encoderMediaType returns the media type of the content type of the encoder without parameters, e.g. "application/json".
+func encoderMediaType(encoder ResponseEncoder) string {
+	mediaType, _, err := mime.ParseMediaType(encoder.ContentType())
+	if err != nil {
+		mediaType, _, _ = strings.Cut(encoder.ContentType(), ";")
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// MediaTypes returns the media types of the encoders, the default one goes first.
+func (e *ResponseEncoders) MediaTypes() []string {
+	return e.mediaTypes
+}
+
+// Negotiate returns the encoder of the most preferred media type of the Accept header.
+func (e *ResponseEncoders) Negotiate(accept string) (ResponseEncoder, bool) {
+	if strings.TrimSpace(accept) == "" {
+		accept = defaultMediaType
+	}
+	for _, mediaRange := range errhttp.AcceptedMediaTypes(accept) {
+		for _, mediaType := range e.mediaTypes {
+			if errhttp.MatchesMediaRange(mediaType, mediaRange) {
+				return e.encoders[mediaType], true
+			}
+		}
+	}
+	return nil, false
+}
+
+var defaultResponseEncoders = NewResponseEncoders(ResponseEncodersParams{})
+
+type responseEncodersKey struct{}
+
+// withResponseEncoders sets the encoders used by WriteResponse in the handlers of the registered routes.
+func withResponseEncoders(ctx context.Context, encoders *ResponseEncoders) context.Context {
+	return context.WithValue(ctx, responseEncodersKey{}, encoders)
+}
+
+func responseEncodersFromContext(ctx context.Context) *ResponseEncoders {
+	if encoders, ok := ctx.Value(responseEncodersKey{}).(*ResponseEncoders); ok && encoders != nil {
+		return encoders
+	}
+	return defaultResponseEncoders
+}
+
+// WriteResponse writes the value in the media type negotiated by the Accept header of the request.
+// The encoders of the http module are used in the handlers of the registered routes, the default ones elsewhere.
+// The status code is taken from the value if it implements StatusCoder.
+// It returns ErrNotAcceptable if no encoder matches the Accept header.
+func WriteResponse(w http.ResponseWriter, req *http.Request, v any) error {
+	encoder, ok := responseEncodersFromContext(req.Context()).Negotiate(req.Header.Get("Accept"))
+	if !ok {
+		return errtrace.Wrap(ErrNotAcceptable)
+	}
+	// the value is encoded before writing the headers to send the encoding error through the error pipeline
+	var body bytes.Buffer
+	if err := encoder.Encode(&body, v); err != nil {
+		return errtrace.Wrap(err)
+	}
+	status := http.StatusOK
+	if coder, ok := v.(StatusCoder); ok && !isNilPointer(v) {
+		status = coder.StatusCode()
+	}
+	w.Header().Set("Content-Type", encoder.ContentType())
+	w.WriteHeader(status)
+	_, err := w.Write(body.Bytes())
+	return errtrace.Wrap(err)
+}
+
+func isNilPointer(v any) bool {
+	value := reflect.ValueOf(v)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
@@ -0,0 +1,176 @@
+package http_test
+
+import (
+	"fmt"
+	"io"
+	netHttp "net/http"
+	"testing"
+
+	"github.com/go-modulus/modulus/http"
+	"github.com/go-modulus/modulus/http/errhttp"
+	"github.com/go-modulus/modulus/test"
+	"github.com/go-modulus/modulus/test/testhttp"
+	"github.com/stretchr/testify/assert"
+)
+
+type textEncoder struct{}
+
+func (textEncoder) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func (textEncoder) Encode(w io.Writer, v any) error {
+	_, err := fmt.Fprintf(w, "%v", v)
+	return err
+}
+
+type postInput struct {
+	ID string `in:"path=id"`
+}
+
+type postOutput struct {
+	ID    string `json:"id" xml:"id,attr"`
+	Title string `json:"title" xml:"title"`
+}
+
+type createdPost struct {
+	ID string `json:"id"`
+}
+
+func (createdPost) StatusCode() int {
+	return netHttp.StatusCreated
+}
+
+func TestProvideTypedRoute(t *testing.T) {
+	t.Parallel()
+	mod := http.NewModule(http.AddResponseEncoder(textEncoder{})).AddProviders(
+		func() http.RouteProvider {
+			return http.ProvideTypedRoute(
+				netHttp.MethodGet, "/posts/{id}", func(req http.RequestWithInput[postInput]) (postOutput, error) {
+					if req.Input.ID == "forbidden" {
+						return postOutput{}, errForbidden
+					}
+					return postOutput{ID: req.Input.ID, Title: "Title"}, nil
+				},
+			)
+		},
+		func() http.RouteProvider {
+			return http.ProvideTypedRoute(
+				netHttp.MethodPost, "/posts", func(req http.RequestWithInput[struct{}]) (createdPost, error) {
+					return createdPost{ID: "42"}, nil
+				},
+			)
+		},
+	)
+	client := testhttp.NewClient(t, test.NewHarness(t, mod))
+
+	t.Run(
+		"json by default", func(t *testing.T) {
+			t.Parallel()
+			client.Get("/posts/1").
+				ExpectStatus(netHttp.StatusOK).
+				ExpectHeader("Content-Type", "application/json; charset=utf-8").
+				ExpectJSON(`{"id": "1", "title": "Title"}`)
+		},
+	)
+
+	t.Run(
+		"xml", func(t *testing.T) {
+			t.Parallel()
+			resp := client.Get("/posts/1").
+				WithHeader("Accept", "application/xml").
+				ExpectStatus(netHttp.StatusOK).
+				ExpectHeader("Content-Type", "application/xml; charset=utf-8")
+
+			assert.Contains(t, string(resp.Body), `<postOutput id="1"><title>Title</title></postOutput>`)
+		},
+	)
+
+	t.Run(
+		"text xml", func(t *testing.T) {
+			t.Parallel()
+			resp := client.Get("/posts/1").
+				WithHeader("Accept", "text/xml").
+				ExpectStatus(netHttp.StatusOK).
+				ExpectHeader("Content-Type", "text/xml; charset=utf-8")
+
+			t.Log("When the text/xml media type is requested")
+			t.Log("	Then the response is XML with the requested media type")
+			assert.Contains(t, string(resp.Body), `<postOutput id="1"><title>Title</title></postOutput>`)
+		},
+	)
+
+	t.Run(
+		"preferred added encoder", func(t *testing.T) {
+			t.Parallel()
+			resp := client.Get("/posts/1").
+				WithHeader("Accept", "application/msgpack, text/*;q=0.9, application/json;q=0.5").
+				ExpectStatus(netHttp.StatusOK).
+				ExpectHeader("Content-Type", "text/plain; charset=utf-8")
+
+			assert.Equal(t, "{1 Title}", string(resp.Body))
+		},
+	)
+
+	t.Run(
+		"not acceptable", func(t *testing.T) {
+			t.Parallel()
+			client.Get("/posts/1").
+				WithHeader("Accept", "application/msgpack, application/json;q=0").
+				ExpectStatus(netHttp.StatusNotAcceptable).
+				ExpectErrorCode("not acceptable")
+		},
+	)
+
+	t.Run(
+		"handler error", func(t *testing.T) {
+			t.Parallel()
+			client.Get("/posts/forbidden").
+				ExpectStatus(netHttp.StatusForbidden).
+				ExpectErrorCode("forbidden")
+		},
+	)
+
+	t.Run(
+		"status coder", func(t *testing.T) {
+			t.Parallel()
+			client.Post("/posts").
+				ExpectStatus(netHttp.StatusCreated).
+				ExpectJSON(`{"id": "42"}`)
+		},
+	)
+}
+
+func TestErrorWriter(t *testing.T) {
+	t.Parallel()
+	mod := http.NewModule().AddProviders(
+		func() http.RouteProvider {
+			return http.ProvideRoute(netHttp.MethodGet, "/posts", fail)
+		},
+	)
+	client := testhttp.NewClient(t, test.NewHarness(t, mod).Env(map[string]string{"HTTP_ERROR_FORMAT": "problem"}))
+
+	t.Run(
+		"configured format", func(t *testing.T) {
+			t.Parallel()
+			var problem errhttp.Problem
+			client.Get("/posts").
+				ExpectStatus(netHttp.StatusForbidden).
+				ExpectHeader("Content-Type", errhttp.ProblemMediaType).
+				DecodeJSON(&problem)
+
+			assert.Equal(t, "forbidden", problem.Code)
+			assert.Equal(t, "/posts", problem.Instance)
+		},
+	)
+
+	t.Run(
+		"format by accept", func(t *testing.T) {
+			t.Parallel()
+			client.Get("/unknown").
+				WithHeader("Accept", "application/json;q=0.5, text/html;q=0.1, application/problem+json").
+				ExpectStatus(netHttp.StatusNotFound).
+				ExpectHeader("Content-Type", errhttp.ProblemMediaType)
+		},
+	)
+}
@@ -2,16 +2,27 @@ package http
 
 import (
 	"net/http"
+	"reflect"
 
 	"github.com/go-modulus/modulus/http/errhttp"
 	"go.uber.org/fx"
 )
 
 type Route struct {
+	// Name is used by URLGenerator to build the URLs of the route. It is optional and must be unique.
+	Name       string
 	Method     string
 	Path       string
 	Handler    http.Handler
 	ErrHandler errhttp.Handler
+	// Input is the type of the input struct decoded by httpin. It is set by ProvideInputRoute and used by OpenAPIGenerator.
+	Input reflect.Type
+	// Output is the type returned by the handler. It is set by ProvideTypedRoute and used by OpenAPIGenerator.
+	Output reflect.Type
+	// Middlewares wrap the handler of the route after the global middlewares. The first one is executed first.
+	Middlewares []Middleware
+	// ErrorPipeline processes the errors of ErrHandler instead of the global one if it is set.
+	ErrorPipeline *errhttp.ErrorPipeline
 }
 
 func (r *Route) IsEmpty() bool {
@@ -23,6 +34,20 @@ type RouteProvider struct {
 	Route Route `group:"http.routes"`
 }
 
+// Named sets the name of the route for URLGenerator:
+//
+//	http.ProvideRoute("GET", "/posts/{id}", handler).Named("post")
+func (p RouteProvider) Named(name string) RouteProvider {
+	p.Route.Name = name
+	return p
+}
+
+// RoutesProvider adds several routes to the http.routes group, e.g. the routes of a RouteGroup.
+type RoutesProvider struct {
+	fx.Out
+	Routes []Route `group:"http.routes,flatten"`
+}
+
 func ProvideRawRoute(method, path string, handler http.Handler) RouteProvider {
 	return RouteProvider{
 		Route: Route{
@@ -39,6 +64,21 @@ func ProvideInputRoute[T any](method, path string, handler InputHandler[T]) Rout
 			Method:     method,
 			Path:       path,
 			ErrHandler: WrapInputHandler(handler),
+			Input:      reflect.TypeFor[T](),
+		},
+	}
+}
+
+// ProvideTypedRoute adds the route returning the output of the handler in the media type accepted by the client,
+// see WriteResponse.
+func ProvideTypedRoute[I any, O any](method, path string, handler TypedHandler[I, O]) RouteProvider {
+	return RouteProvider{
+		Route: Route{
+			Method:     method,
+			Path:       path,
+			ErrHandler: WrapTypedHandler(handler),
+			Input:      reflect.TypeFor[I](),
+			Output:     reflect.TypeFor[O](),
 		},
 	}
 }
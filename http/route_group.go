@@ -0,0 +1,113 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-modulus/modulus/http/errhttp"
+)
+
+// RouteGroup is a set of routes with a common path prefix, middlewares and an optional error pipeline.
+// Provide it to the http.routes group with ProvideRouteGroup:
+//
+//	func NewAdminRoutes(auth *AdminAuth, users *UsersHandler) http.RoutesProvider {
+//		return http.ProvideRouteGroup(
+//			http.NewRouteGroup("/admin").
+//				SetMiddleware(100, auth.Middleware).
+//				Handle("GET", "/users", users.List),
+//		)
+//	}
+type RouteGroup struct {
+	prefix        string
+	pipeline      *Pipeline
+	errorPipeline *errhttp.ErrorPipeline
+	routes        []Route
+	groups        []*RouteGroup
+}
+
+func NewRouteGroup(prefix string) *RouteGroup {
+	return &RouteGroup{
+		prefix:   prefix,
+		pipeline: &Pipeline{},
+	}
+}
+
+// SetMiddleware adds the middleware to the routes of the group. Middlewares with a lower rank are executed first.
+// They are executed after the global middlewares and the middlewares of the parent groups.
+func (g *RouteGroup) SetMiddleware(rank int, middleware Middleware) *RouteGroup {
+	g.pipeline.SetMiddleware(rank, middleware)
+	return g
+}
+
+// SetErrorPipeline sets the error pipeline used by the routes of the group instead of the global one.
+// Routes and nested groups with their own error pipeline keep it.
+func (g *RouteGroup) SetErrorPipeline(errorPipeline *errhttp.ErrorPipeline) *RouteGroup {
+	g.errorPipeline = errorPipeline
+	return g
+}
+
+// Add adds the routes to the group. Their paths are relative to the prefix of the group.
+func (g *RouteGroup) Add(routes ...Route) *RouteGroup {
+	g.routes = append(g.routes, routes...)
+	return g
+}
+
+// Handle adds the route with the handler returning errors.
+func (g *RouteGroup) Handle(method, path string, handler errhttp.Handler) *RouteGroup {
+	return g.Add(Route{Method: method, Path: path, ErrHandler: handler})
+}
+
+// HandleRaw adds the route with the standard http.Handler.
+func (g *RouteGroup) HandleRaw(method, path string, handler http.Handler) *RouteGroup {
+	return g.Add(Route{Method: method, Path: path, Handler: handler})
+}
+
+// Name sets the name of the last added route for URLGenerator.
+func (g *RouteGroup) Name(name string) *RouteGroup {
+	if len(g.routes) > 0 {
+		g.routes[len(g.routes)-1].Name = name
+	}
+	return g
+}
+
+// AddGroup adds the nested group. Its prefix is relative to the prefix of the group.
+func (g *RouteGroup) AddGroup(groups ...*RouteGroup) *RouteGroup {
+	g.groups = append(g.groups, groups...)
+	return g
+}
+
+// Routes returns the routes of the group and the nested groups with the full paths, the middlewares
+// and the error pipelines.
+func (g *RouteGroup) Routes() []Route {
+	routes := append([]Route{}, g.routes...)
+	for _, group := range g.groups {
+		routes = append(routes, group.Routes()...)
+	}
+	middlewares := g.pipeline.GetMiddlewares()
+	for i, route := range routes {
+		route.Path = joinPath(g.prefix, route.Path)
+		route.Middlewares = append(append([]Middleware{}, middlewares...), route.Middlewares...)
+		if route.ErrorPipeline == nil {
+			route.ErrorPipeline = g.errorPipeline
+		}
+		routes[i] = route
+	}
+	return routes
+}
+
+func ProvideRouteGroup(group *RouteGroup) RoutesProvider {
+	return RoutesProvider{
+		Routes: group.Routes(),
+	}
+}
+
+func joinPath(prefix, path string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if path == "" {
+		return prefix
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return prefix + path
+}
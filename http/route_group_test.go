@@ -0,0 +1,99 @@
+package http_test
+
+import (
+	"context"
+	netHttp "net/http"
+	"testing"
+
+	"github.com/go-modulus/modulus/errors/erruser"
+	"github.com/go-modulus/modulus/http"
+	"github.com/go-modulus/modulus/http/errhttp"
+	"github.com/go-modulus/modulus/test"
+	"github.com/go-modulus/modulus/test/testhttp"
+	"github.com/stretchr/testify/assert"
+)
+
+var errForbidden = errhttp.ErrWithHttpCode(erruser.New("forbidden", "Forbidden"), netHttp.StatusForbidden)
+
+func addHeader(value string) http.Middleware {
+	return func(next netHttp.Handler) netHttp.Handler {
+		return netHttp.HandlerFunc(
+			func(w netHttp.ResponseWriter, r *netHttp.Request) {
+				w.Header().Add("X-Group", value)
+				next.ServeHTTP(w, r)
+			},
+		)
+	}
+}
+
+func fail(netHttp.ResponseWriter, *netHttp.Request) error {
+	return errForbidden
+}
+
+func TestRouteGroup_Routes(t *testing.T) {
+	t.Parallel()
+	errorPipeline := &errhttp.ErrorPipeline{}
+	v1 := http.NewRouteGroup("v1").
+		SetMiddleware(100, addHeader("v1")).
+		Handle(netHttp.MethodGet, "/users", fail)
+	group := http.NewRouteGroup("/api/").
+		SetMiddleware(200, addHeader("api-200")).
+		SetMiddleware(100, addHeader("api-100")).
+		SetErrorPipeline(errorPipeline).
+		Handle(netHttp.MethodGet, "/", fail).
+		AddGroup(v1)
+
+	routes := group.Routes()
+
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "/api/", routes[0].Path)
+	assert.Len(t, routes[0].Middlewares, 2)
+	assert.Same(t, errorPipeline, routes[0].ErrorPipeline)
+	assert.Equal(t, "/api/v1/users", routes[1].Path)
+	assert.Len(t, routes[1].Middlewares, 3)
+	assert.Same(t, errorPipeline, routes[1].ErrorPipeline)
+}
+
+func TestRouteGroup(t *testing.T) {
+	t.Parallel()
+	adminPipeline := &errhttp.ErrorPipeline{}
+	adminPipeline.SetProcessor(
+		100, func(ctx context.Context, err error) error {
+			return errhttp.ErrWithHttpCode(erruser.New("unauthorized", "Log in as admin"), netHttp.StatusUnauthorized)
+		},
+	)
+	mod := http.NewModule().AddProviders(
+		func() http.RoutesProvider {
+			return http.ProvideRouteGroup(
+				http.NewRouteGroup("/admin").
+					SetMiddleware(100, addHeader("admin")).
+					SetErrorPipeline(adminPipeline).
+					Handle(netHttp.MethodGet, "/users", fail),
+			)
+		},
+		func() http.RoutesProvider {
+			return http.ProvideRouteGroup(
+				http.NewRouteGroup("/api/v1").
+					SetMiddleware(100, addHeader("api")).
+					Handle(netHttp.MethodGet, "/users", fail),
+			)
+		},
+		func() http.RouteProvider {
+			return http.ProvideRoute(netHttp.MethodGet, "/users", fail)
+		},
+	)
+	client := testhttp.NewClient(t, test.NewHarness(t, mod))
+
+	client.Get("/admin/users").
+		ExpectStatus(netHttp.StatusUnauthorized).
+		ExpectHeader("X-Group", "admin").
+		ExpectErrorCode("unauthorized")
+	client.Get("/api/v1/users").
+		ExpectStatus(netHttp.StatusForbidden).
+		ExpectHeader("X-Group", "api").
+		ExpectErrorCode("forbidden")
+	client.Get("/users").
+		ExpectStatus(netHttp.StatusForbidden).
+		ExpectHeader("X-Group", "").
+		ExpectErrorCode("forbidden")
+}
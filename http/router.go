@@ -99,21 +99,35 @@ func (rb *responseBuffer) flush(w http.ResponseWriter) {
 	}
 }
 
+// NewDefaultRouter returns the router writing the not found and method not allowed errors as the JSON envelope.
 func NewDefaultRouter(errorPipeline *errhttp.ErrorPipeline, config ServeConfig) Router {
+	return NewDefaultRouterWithErrorWriter(errorPipeline, errhttp.EnvelopeErrorWriter{}, config)
+}
+
+// NewDefaultRouterWithErrorWriter returns the router writing the not found and method not allowed errors
+// by the error writer. The http module uses it with the writer selecting the format by HTTP_ERROR_FORMAT
+// and the Accept header.
+func NewDefaultRouterWithErrorWriter(
+	errorPipeline *errhttp.ErrorPipeline,
+	errorWriter errhttp.ErrorWriter,
+	config ServeConfig,
+) Router {
 	r := &DefaultRouter{
 		mux: http.NewServeMux(),
 	}
 	r.MethodNotAllowed(
-		errhttp.WrapHandler(
+		errhttp.WrapHandlerWithWriter(
 			errorPipeline,
+			errorWriter,
 			func(w http.ResponseWriter, req *http.Request) error {
 				return ErrMethodNotAllowed
 			},
 		),
 	)
 	r.NotFound(
-		errhttp.WrapHandler(
+		errhttp.WrapHandlerWithWriter(
 			errorPipeline,
+			errorWriter,
 			func(w http.ResponseWriter, req *http.Request) error {
 				return ErrNotFound
 			},
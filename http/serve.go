@@ -27,6 +27,8 @@ type Serve struct {
 	routes        []Route
 	middlewares   []Middleware
 	errorPipeline *errhttp.ErrorPipeline
+	errorWriter   errhttp.ErrorWriter
+	encoders      *ResponseEncoders
 	logger        *slog.Logger
 	config        ServeConfig
 }
@@ -38,8 +40,10 @@ type ServeParams struct {
 	Router   Router
 	Routes   []Route `group:"http.routes"`
 	Pipeline *Pipeline
-	// @todo: think on placing this in each route to be able to override it for specific routes
+	// ErrorPipeline is used by the routes without their own error pipeline, see Route.ErrorPipeline and RouteGroup.
 	ErrorPipeline *errhttp.ErrorPipeline
+	ErrorWriter   errhttp.ErrorWriter
+	Encoders      *ResponseEncoders
 	Logger        *slog.Logger
 	Config        ServeConfig
 }
@@ -57,6 +61,8 @@ func NewServe(params ServeParams) *Serve {
 		config:        params.Config,
 		middlewares:   middlewares,
 		errorPipeline: params.ErrorPipeline,
+		errorWriter:   params.ErrorWriter,
+		encoders:      params.Encoders,
 	}
 }
 
@@ -78,32 +84,7 @@ func (s *Serve) Invoke(ctx context.Context, cmd *cli.Command) error {
 		ErrorLog:     slog.NewLogLogger(logger.Handler(), slog.LevelError),
 	}
 
-	if len(s.middlewares) > 0 {
-		for _, middleware := range s.middlewares {
-			s.router.Use(middleware)
-		}
-
-		logger.Info("registering global middlewares", slog.Int("count", len(s.middlewares)))
-	}
-
-	count := 0
-	for _, route := range s.routes {
-		if route.IsEmpty() {
-			continue
-		}
-		logger.Debug(
-			"registering route",
-			slog.String("method", route.Method),
-			slog.String("path", route.Path),
-		)
-		if route.Handler != nil {
-			s.router.Method(route.Method, route.Path, route.Handler)
-		} else {
-			s.router.Method(route.Method, route.Path, errhttp.WrapHandler(s.errorPipeline, route.ErrHandler))
-		}
-		count++
-	}
-	logger.Info("registered routes", slog.Int("count", count))
+	registerRoutes(s.router, s.middlewares, s.routes, s.errorPipeline, s.errorWriter, s.encoders, logger)
 
 	return s.runner.Run(
 		ctx, func(ctx context.Context) error {
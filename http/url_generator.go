@@ -0,0 +1,102 @@
+package http
+
+import (
+	"net/url"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/go-modulus/modulus/errors/errsys"
+	"go.uber.org/fx"
+)
+
+var (
+	ErrUnknownRoute = errsys.New(
+		"unknown route",
+		"There is no route with this name. Set the name with RouteProvider.Named or Route.Name",
+	)
+	ErrMissingRouteParam = errsys.New(
+		"missing route param",
+		"Pass the values of all wildcards of the route path",
+	)
+	ErrDuplicateRouteName = errsys.New(
+		"duplicate route name",
+		"Routes with different paths must have different names",
+	)
+)
+
+type URLGeneratorParams struct {
+	fx.In
+
+	Routes []Route `group:"http.routes"`
+}
+
+// URLGenerator builds the paths of the named routes.
+type URLGenerator struct {
+	paths map[string]string
+}
+
+func NewURLGenerator(params URLGeneratorParams) (*URLGenerator, error) {
+	g := &URLGenerator{paths: make(map[string]string)}
+	for _, route := range params.Routes {
+		if route.Name == "" {
+			continue
+		}
+		// the same name can be used for several methods of the same path
+		if path, ok := g.paths[route.Name]; ok && path != route.Path {
+			return nil, errtrace.Errorf("%s: %w", route.Name, ErrDuplicateRouteName)
+		}
+		g.paths[route.Name] = route.Path
+	}
+	return g, nil
+}
+
+// URL returns the path of the named route with the wildcards replaced by the params.
+// Patterns of net/http.ServeMux are supported: "/posts/{id}", "/files/{path...}" and "/{$}".
+// The values are escaped, the value of a {name...} wildcard may contain slashes.
+// Params that are not used in the path are added to the query string.
+func (g *URLGenerator) URL(name string, params map[string]string) (string, error) {
+	pattern, ok := g.paths[name]
+	if !ok {
+		return "", errtrace.Errorf("%s: %w", name, ErrUnknownRoute)
+	}
+	used := make(map[string]struct{})
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		wildcard := segment[1 : len(segment)-1]
+		if wildcard == "$" {
+			segments[i] = ""
+			continue
+		}
+		wildcard, remaining := strings.CutSuffix(wildcard, "...")
+		// a {name} wildcard matches a non-empty segment only
+		value, ok := params[wildcard]
+		if !ok || (value == "" && !remaining) {
+			return "", errtrace.Errorf("%s of %s: %w", wildcard, name, ErrMissingRouteParam)
+		}
+		used[wildcard] = struct{}{}
+		if remaining {
+			parts := strings.Split(value, "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+			segments[i] = strings.Join(parts, "/")
+			continue
+		}
+		segments[i] = url.PathEscape(value)
+	}
+	path := strings.Join(segments, "/")
+
+	query := make(url.Values)
+	for key, value := range params {
+		if _, ok := used[key]; !ok {
+			query.Set(key, value)
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path, nil
+}
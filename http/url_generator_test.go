@@ -0,0 +1,92 @@
+package http_test
+
+import (
+	netHttp "net/http"
+	"testing"
+
+	"github.com/go-modulus/modulus/http"
+	"github.com/go-modulus/modulus/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLGenerator_URL(t *testing.T) {
+	t.Parallel()
+	mod := http.NewModule().AddProviders(
+		func() http.RouteProvider {
+			return http.ProvideRoute(netHttp.MethodGet, "/posts/{id}", fail).Named("post")
+		},
+		func() http.RouteProvider {
+			return http.ProvideRoute(netHttp.MethodDelete, "/posts/{id}", fail).Named("post")
+		},
+		func() http.RoutesProvider {
+			return http.ProvideRouteGroup(
+				http.NewRouteGroup("/files").
+					Handle(netHttp.MethodGet, "/{$}", fail).Name("files").
+					Handle(netHttp.MethodGet, "/{bucket}/{path...}", fail).Name("file"),
+			)
+		},
+	)
+	generator := test.Get[*http.URLGenerator](test.NewHarness(t, mod))
+
+	cases := []struct {
+		name     string
+		route    string
+		params   map[string]string
+		expected string
+	}{
+		{"param", "post", map[string]string{"id": "42"}, "/posts/42"},
+		{"escaped param", "post", map[string]string{"id": "a b/c"}, "/posts/a%20b%2Fc"},
+		{"query", "post", map[string]string{"id": "42", "lang": "en"}, "/posts/42?lang=en"},
+		{"exact match", "files", nil, "/files/"},
+		{"remaining segments", "file", map[string]string{"bucket": "docs", "path": "a/b c.txt"}, "/files/docs/a/b%20c.txt"},
+		{"empty remaining segments", "file", map[string]string{"bucket": "docs", "path": ""}, "/files/docs/"},
+	}
+	for _, c := range cases {
+		t.Run(
+			c.name, func(t *testing.T) {
+				t.Parallel()
+				url, err := generator.URL(c.route, c.params)
+
+				require.NoError(t, err)
+				assert.Equal(t, c.expected, url)
+			},
+		)
+	}
+
+	t.Run(
+		"unknown route", func(t *testing.T) {
+			t.Parallel()
+			_, err := generator.URL("unknown", nil)
+
+			require.ErrorIs(t, err, http.ErrUnknownRoute)
+		},
+	)
+
+	t.Run(
+		"missing param", func(t *testing.T) {
+			t.Parallel()
+			_, err := generator.URL("file", map[string]string{"path": "a"})
+
+			require.ErrorIs(t, err, http.ErrMissingRouteParam)
+
+			_, err = generator.URL("post", map[string]string{"id": ""})
+
+			require.ErrorIs(t, err, http.ErrMissingRouteParam)
+		},
+	)
+}
+
+func TestNewURLGenerator(t *testing.T) {
+	t.Parallel()
+	_, err := http.NewURLGenerator(
+		http.URLGeneratorParams{
+			Routes: []http.Route{
+				{Name: "post", Path: "/posts/{id}"},
+				{Name: "post", Path: "/articles/{id}"},
+			},
+		},
+	)
+
+	require.ErrorIs(t, err, http.ErrDuplicateRouteName)
+}
@@ -0,0 +1,240 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-modulus/modulus/module"
+	slogmulti "github.com/samber/slog-multi"
+	"go.uber.org/zap"
+)
+
+// LogEntry is a log record saved by Capture.
+// Attrs hold the attributes of the record with the tags of the context and the attributes of the logger.
+// Groups and slog.LogValuer values, e.g. errlog.Error, are converted to nested maps.
+type LogEntry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]any
+}
+
+// Attr returns the value of the attribute by the path of the keys separated by dots, e.g. "error.meta.requestId".
+func (e LogEntry) Attr(path string) (any, bool) {
+	var value any = e.Attrs
+	for _, key := range strings.Split(path, ".") {
+		group, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		value, ok = group[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// LogFilter selects the entries in Capture.Filter.
+type LogFilter func(entry LogEntry) bool
+
+func ByLevel(level slog.Level) LogFilter {
+	return func(entry LogEntry) bool {
+		return entry.Level == level
+	}
+}
+
+func ByMessage(message string) LogFilter {
+	return func(entry LogEntry) bool {
+		return entry.Message == message
+	}
+}
+
+// ByAttr selects the entries having the attribute with the value. See LogEntry.Attr for the path format.
+func ByAttr(path string, value any) LogFilter {
+	return func(entry LogEntry) bool {
+		actual, ok := entry.Attr(path)
+		return ok && reflect.DeepEqual(actual, value)
+	}
+}
+
+type captureStorage struct {
+	mu      sync.Mutex
+	entries []LogEntry
+}
+
+// Capture is the slog handler saving the log records in memory to check them in tests.
+// The module provides it with the CaptureLogs option.
+type Capture struct {
+	storage *captureStorage
+	attrs   map[string]any
+	groups  []string
+}
+
+func NewCapture() *Capture {
+	return &Capture{
+		storage: &captureStorage{},
+		attrs:   make(map[string]any),
+	}
+}
+
+// CaptureLogs makes the logger module save all records in *Capture provided by the module.
+// The records are written to the zap logger as well. It is intended for tests.
+func CaptureLogs() module.Option {
+	return func(m *module.Module) *module.Module {
+		return m.AddProviders(NewCapture).
+			SetOverriddenProvider("logger.Slog", NewCapturingSlog)
+	}
+}
+
+// NewCapturingSlog returns the logger of NewSlog that also sends the records to the capture.
+func NewCapturingSlog(zapLogger *zap.Logger, capture *Capture) *slog.Logger {
+	return newSlog(slogmulti.Fanout(newZapHandler(zapLogger), capture))
+}
+
+func (c *Capture) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (c *Capture) Handle(_ context.Context, record slog.Record) error {
+	attrs := copyAttrs(c.attrs)
+	group := groupMap(attrs, c.groups)
+	record.Attrs(
+		func(attr slog.Attr) bool {
+			addAttr(group, attr)
+			return true
+		},
+	)
+	c.storage.mu.Lock()
+	defer c.storage.mu.Unlock()
+	c.storage.entries = append(
+		c.storage.entries, LogEntry{
+			Time:    record.Time,
+			Level:   record.Level,
+			Message: record.Message,
+			Attrs:   attrs,
+		},
+	)
+	return nil
+}
+
+func (c *Capture) WithAttrs(attrs []slog.Attr) slog.Handler {
+	result := &Capture{
+		storage: c.storage,
+		attrs:   copyAttrs(c.attrs),
+		groups:  c.groups,
+	}
+	group := groupMap(result.attrs, result.groups)
+	for _, attr := range attrs {
+		addAttr(group, attr)
+	}
+	return result
+}
+
+func (c *Capture) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return c
+	}
+	return &Capture{
+		storage: c.storage,
+		attrs:   c.attrs,
+		groups:  append(append([]string{}, c.groups...), name),
+	}
+}
+
+// Entries returns all saved entries in the order of logging.
+func (c *Capture) Entries() []LogEntry {
+	c.storage.mu.Lock()
+	defer c.storage.mu.Unlock()
+	return append([]LogEntry{}, c.storage.entries...)
+}
+
+// Filter returns the entries matching all filters.
+func (c *Capture) Filter(filters ...LogFilter) []LogEntry {
+	var result []LogEntry
+	for _, entry := range c.Entries() {
+		matched := true
+		for _, filter := range filters {
+			if !filter(entry) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// Reset removes the saved entries.
+func (c *Capture) Reset() {
+	c.storage.mu.Lock()
+	defer c.storage.mu.Unlock()
+	c.storage.entries = nil
+}
+
+// groupMap returns the map of the nested group creating the missing ones.
+func groupMap(attrs map[string]any, groups []string) map[string]any {
+	for _, name := range groups {
+		group, ok := attrs[name].(map[string]any)
+		if !ok {
+			group = make(map[string]any)
+			attrs[name] = group
+		}
+		attrs = group
+	}
+	return attrs
+}
+
+func addAttr(group map[string]any, attr slog.Attr) {
+	value := attrValue(attr.Value)
+	if attr.Key == "" {
+		// attributes of an inline group are added to the parent group
+		if inline, ok := value.(map[string]any); ok {
+			maps.Copy(group, inline)
+		}
+		return
+	}
+	group[attr.Key] = value
+}
+
+func attrValue(value slog.Value) any {
+	value = value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		return attrsMap(value.Group())
+	case slog.KindAny:
+		if attrs, ok := value.Any().([]slog.Attr); ok {
+			return attrsMap(attrs)
+		}
+		return value.Any()
+	default:
+		return value.Any()
+	}
+}
+
+func attrsMap(attrs []slog.Attr) map[string]any {
+	result := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		addAttr(result, attr)
+	}
+	return result
+}
+
+// copyAttrs copies the attributes with the nested groups.
+func copyAttrs(attrs map[string]any) map[string]any {
+	result := make(map[string]any, len(attrs))
+	for key, value := range attrs {
+		if group, ok := value.(map[string]any); ok {
+			value = copyAttrs(group)
+		}
+		result[key] = value
+	}
+	return result
+}
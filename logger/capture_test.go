@@ -0,0 +1,83 @@
+package logger_test
+
+import (
+	"context"
+	"log/slog"
+	netHttp "net/http"
+	"testing"
+
+	"github.com/go-modulus/modulus/errors/errsys"
+	"github.com/go-modulus/modulus/http"
+	"github.com/go-modulus/modulus/logger"
+	"github.com/go-modulus/modulus/test"
+	"github.com/go-modulus/modulus/test/testhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapture(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"capture the records with the tags and groups", func(t *testing.T) {
+			t.Parallel()
+			var capture *logger.Capture
+			var log *slog.Logger
+			test.NewHarness(t, logger.NewModule(logger.CaptureLogs())).Populate(&capture, &log).Start()
+			ctx := logger.AddTags(context.Background(), "requestId", "123")
+
+			log.With("component", "test").
+				WithGroup("request").
+				InfoContext(ctx, "handled", slog.Int("status", 200), slog.Group("user", slog.String("id", "1")))
+			log.Warn("slow")
+
+			entries := capture.Filter(logger.ByMessage("handled"), logger.ByLevel(slog.LevelInfo))
+			require.Len(t, entries, 1)
+			assert.Equal(
+				t, map[string]any{
+					"component": "test",
+					"request": map[string]any{
+						"status":    int64(200),
+						"user":      map[string]any{"id": "1"},
+						"requestId": "123",
+					},
+				}, entries[0].Attrs,
+			)
+			assert.Len(t, capture.Filter(logger.ByAttr("request.user.id", "1")), 1)
+			assert.Len(t, capture.Entries(), 2)
+
+			capture.Reset()
+
+			assert.Empty(t, capture.Entries())
+		},
+	)
+
+	t.Run(
+		"capture the errors logged by the error pipeline", func(t *testing.T) {
+			t.Parallel()
+			errFailed := errsys.New("failed", "Cannot process")
+			var capture *logger.Capture
+			h := test.NewHarness(
+				t,
+				logger.NewModule(logger.CaptureLogs()),
+				http.NewModule().AddProviders(
+					func() http.RouteProvider {
+						return http.ProvideRoute(
+							netHttp.MethodGet, "/fail", func(netHttp.ResponseWriter, *netHttp.Request) error {
+								return errFailed
+							},
+						)
+					},
+				),
+			).Populate(&capture)
+
+			testhttp.NewClient(t, h).Get("/fail").ExpectStatus(netHttp.StatusInternalServerError)
+
+			entries := capture.Filter(logger.ByLevel(slog.LevelError), logger.ByAttr("error.message", "failed"))
+			require.Len(t, entries, 1)
+			hint, _ := entries[0].Attr("error.hint")
+			assert.Equal(t, "Cannot process", hint)
+			_, ok := entries[0].Attr("requestId")
+			assert.True(t, ok)
+		},
+	)
+}
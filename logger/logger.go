@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"braces.dev/errtrace"
+	"github.com/go-modulus/modulus/module"
 	slogformatter "github.com/samber/slog-formatter"
 	slogmulti "github.com/samber/slog-multi"
 	slogzap "github.com/samber/slog-zap/v2"
@@ -13,14 +14,33 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-func NewLogger(config ModuleConfig) (*zap.Logger, error) {
+// NewLevel returns the minimum level of the log messages. It is changed by ReloadLevel when LOGGER_LEVEL is reloaded.
+func NewLevel(config ModuleConfig) (zap.AtomicLevel, error) {
 	level, err := zap.ParseAtomicLevel(config.Level)
 	if err != nil {
-		return nil, errtrace.Errorf(
+		return level, errtrace.Errorf(
 			`invalid logger level "%s". Use "debug", "info", "warn" or "error"`,
 			config.Level,
 		)
 	}
+	return level, nil
+}
+
+// ReloadLevel changes the level of the logger when the config is reloaded.
+func ReloadLevel(level zap.AtomicLevel, config *module.Reloadable[ModuleConfig], logger *zap.Logger) {
+	config.Subscribe(
+		func(cfg ModuleConfig) {
+			newLevel, err := zapcore.ParseLevel(cfg.Level)
+			if err != nil || newLevel == level.Level() {
+				return
+			}
+			level.SetLevel(newLevel)
+			logger.Info("logger level is changed", zap.String("level", newLevel.String()))
+		},
+	)
+}
+
+func NewLogger(config ModuleConfig, level zap.AtomicLevel) (*zap.Logger, error) {
 	if config.Type != "json" && config.Type != "console" {
 		return nil, errtrace.Errorf(
 			`invalid logger type "%s". Use "json" or "console"`,
@@ -56,7 +76,16 @@ func NewLogger(config ModuleConfig) (*zap.Logger, error) {
 func NewSlog(
 	zapLogger *zap.Logger,
 ) *slog.Logger {
-	handler := slogzap.Option{Logger: zapLogger.WithOptions(zap.AddCallerSkip(8))}.NewZapHandler()
+	return newSlog(newZapHandler(zapLogger))
+}
+
+func newZapHandler(zapLogger *zap.Logger) slog.Handler {
+	return slogzap.Option{Logger: zapLogger.WithOptions(zap.AddCallerSkip(8))}.NewZapHandler()
+}
+
+// newSlog returns the logger passing the records to the handler through the middlewares adding the context tags
+// and formatting the values.
+func newSlog(handler slog.Handler) *slog.Logger {
 	errorFormattingMiddleware := slogformatter.NewFormatterMiddleware(
 		slogformatter.TimeFormatter(time.RFC3339Nano, time.UTC),
 	)
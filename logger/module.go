@@ -1,6 +1,9 @@
 package logger
 
 import (
+	"context"
+
+	"github.com/go-modulus/modulus/errors/erruser"
 	"github.com/go-modulus/modulus/module"
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxevent"
@@ -17,12 +20,28 @@ type ModuleConfig struct {
 func NewModule(options ...module.Option) *module.Module {
 	return module.NewModule("logger").
 		AddProviders(
+			NewLevel,
 			NewLogger,
-			NewSlog,
-		).InitConfig(ModuleConfig{}).
+		).
+		SetOverriddenProvider("logger.Slog", NewSlog).
+		AddInvokes(ReloadLevel).
+		WithOptions(module.InitReloadableConfig(ModuleConfig{})).
 		WithOptions(options...)
 }
 
+// Validate checks the level and the type of the logger.
+// An invalid level set while the application is running is ignored, so the current level is kept.
+func (c ModuleConfig) Validate(context.Context) error {
+	var errs []error
+	if _, err := zap.ParseAtomicLevel(c.Level); err != nil {
+		errs = append(errs, erruser.New("LOGGER_LEVEL", `Use "debug", "info", "warn" or "error"`))
+	}
+	if c.Type != "json" && c.Type != "console" {
+		errs = append(errs, erruser.New("LOGGER_TYPE", `Use "json" or "console"`))
+	}
+	return erruser.NewValidationError(errs...)
+}
+
 func SetConfig(config ModuleConfig) module.Option {
 	return func(m *module.Module) *module.Module {
 		return m.InitConfig(config)
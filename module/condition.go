@@ -0,0 +1,133 @@
+package module
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"strconv"
+
+	"go.uber.org/fx"
+)
+
+type condition struct {
+	description string
+	predicate   func(m *Module) bool
+}
+
+// moduleDecision is the result of checking the conditions of a module during BuildFx.
+type moduleDecision struct {
+	module  string
+	enabled bool
+	reason  string
+}
+
+// EnableIf makes the module conditional. The module is built only when all its predicates return true.
+// Otherwise, BuildFx excludes it together with its providers, invokes, CLI commands, tagged providers and configs.
+// Predicates are checked when BuildFx is called. The description is used to log the decision.
+func (m *Module) EnableIf(description string, predicate func() bool) *Module {
+	m.conditions = append(
+		m.conditions, condition{
+			description: description,
+			predicate: func(*Module) bool {
+				return predicate()
+			},
+		},
+	)
+	return m
+}
+
+// EnableIfEnv enables the module only when the environment variable is set to a true value
+// accepted by strconv.ParseBool, e.g. "true" or "1".
+// The variable is looked up in the env of Overrides (e.g. set by test.Harness.Env) first, then in the process environment.
+func EnableIfEnv(key string) Option {
+	return func(m *Module) *Module {
+		m.conditions = append(
+			m.conditions, condition{
+				description: "env " + key,
+				predicate: func(m *Module) bool {
+					value, ok := m.env[key]
+					if !ok {
+						value = os.Getenv(key)
+					}
+					enabled, err := strconv.ParseBool(value)
+					return err == nil && enabled
+				},
+			},
+		)
+		return m
+	}
+}
+
+// EnableIfConfig enables the module only when the predicate returns true for the module config of type T.
+// T must be the config struct type passed to InitConfig, not a pointer or an interface, otherwise the option panics.
+// The config is the last value passed to InitConfig before BuildFx is called.
+// If the module has no config of type T, the module is disabled.
+func EnableIfConfig[T any](predicate func(config T) bool) Option {
+	return func(m *Module) *Module {
+		t := reflect.TypeFor[T]()
+		if t.Kind() != reflect.Struct {
+			panic(fmt.Sprintf("module %s: EnableIfConfig needs the config struct type passed to InitConfig, got %s", m.name, t))
+		}
+		name := configTypeName(t)
+		m.conditions = append(
+			m.conditions, condition{
+				description: "config " + name,
+				predicate: func(m *Module) bool {
+					config, ok := m.configs[name].(T)
+					return ok && predicate(config)
+				},
+			},
+		)
+		return m
+	}
+}
+
+// IsConditional returns true if the module has conditions set by EnableIf or similar options.
+func (m *Module) IsConditional() bool {
+	return len(m.conditions) > 0
+}
+
+// IsEnabled checks all module conditions.
+func (m *Module) IsEnabled() bool {
+	enabled, _ := m.checkConditions()
+	return enabled
+}
+
+// checkConditions returns false and the description of the first failed condition if the module is disabled.
+func (m *Module) checkConditions() (bool, string) {
+	for _, c := range m.conditions {
+		if !c.predicate(m) {
+			return false, c.description
+		}
+	}
+	return true, ""
+}
+
+type decisionLoggerParams struct {
+	fx.In
+
+	Logger *slog.Logger `optional:"true"`
+}
+
+// logDecisions returns an invoke that logs which conditional modules are enabled or disabled.
+func logDecisions(decisions []moduleDecision) interface{} {
+	return func(params decisionLoggerParams) {
+		logger := params.Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger = logger.With(slog.String("component", "module"))
+		for _, d := range decisions {
+			if d.enabled {
+				logger.Debug("conditional module is enabled", slog.String("module", d.module))
+				continue
+			}
+			logger.Info(
+				"conditional module is disabled",
+				slog.String("module", d.module),
+				slog.String("condition", d.reason),
+			)
+		}
+	}
+}
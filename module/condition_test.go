@@ -0,0 +1,130 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+func TestModule_EnableIf(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"disabled module is excluded with its dependencies", func(t *testing.T) {
+			t.Parallel()
+			dep := NewModule("dep").AddProviders(NewAObj)
+			m := NewModule("optional").
+				AddDependencies(dep).
+				AddProviders(NewA).
+				EnableIf(
+					"feature flag", func() bool {
+						return false
+					},
+				)
+			root := NewModule("root").AddDependencies(m)
+
+			g := NewGraph(root)
+			require.Len(t, g.Modules, 1)
+			assert.Equal(t, "root", g.Modules[0].Name)
+
+			var a InterfaceA
+			app := fx.New(BuildFx(root), fx.NopLogger, fx.Populate(&a))
+			require.Error(t, app.Err())
+		},
+	)
+
+	t.Run(
+		"enabled module is built", func(t *testing.T) {
+			t.Parallel()
+			m := NewModule("optional").
+				AddProviders(NewA).
+				EnableIf(
+					"feature flag", func() bool {
+						return true
+					},
+				)
+
+			var a InterfaceA
+			app := fx.New(BuildFx(m), fx.NopLogger, fx.Populate(&a))
+			require.NoError(t, app.Err())
+			assert.Equal(t, "A", a.MethodA())
+		},
+	)
+
+	t.Run(
+		"enable by config", func(t *testing.T) {
+			t.Parallel()
+			type Config struct {
+				Enabled bool   `env:"CONDITION_TEST_ENABLED, default=false"`
+				Secret  string `env:"CONDITION_TEST_SECRET, default=secret"`
+			}
+			enabledByConfig := EnableIfConfig[Config](
+				func(config Config) bool {
+					return config.Enabled
+				},
+			)
+			disabled := NewModule("disabled").
+				InitConfig(Config{}).
+				WithOptions(enabledByConfig)
+			enabled := NewModule("enabled").
+				InitConfig(Config{Enabled: true}).
+				WithOptions(enabledByConfig)
+
+			assert.False(t, disabled.IsEnabled())
+			assert.True(t, enabled.IsEnabled())
+
+			t.Log("When the manifestos are generated")
+			t.Log("	Then conditional dependencies and their env variables are listed regardless of the conditions")
+			manifesto := NewManifesto(NewModule("root").AddDependencies(disabled, enabled), "root", "", "1.0.0")
+			assert.Equal(t, []string{"disabled", "enabled"}, manifesto.Install.Dependencies)
+			assert.Len(t, NewManifesto(disabled, "disabled", "", "1.0.0").Install.EnvVars, 2)
+			assert.Len(t, NewManifesto(enabled, "enabled", "", "1.0.0").Install.EnvVars, 2)
+		},
+	)
+
+	t.Run(
+		"enable by a config interface", func(t *testing.T) {
+			t.Parallel()
+			enabledByConfig := EnableIfConfig[InterfaceA](
+				func(config InterfaceA) bool {
+					return true
+				},
+			)
+
+			t.Log("When the option with a type that is not a config struct is applied")
+			t.Log("	Then it panics with the name of the type")
+			assert.PanicsWithValue(
+				t,
+				"module optional: EnableIfConfig needs the config struct type passed to InitConfig, got module.InterfaceA",
+				func() {
+					NewModule("optional").WithOptions(enabledByConfig)
+				},
+			)
+		},
+	)
+}
+
+func TestEnableIfEnv(t *testing.T) {
+	t.Setenv("CONDITION_TEST_FLAG", "true")
+	m := NewModule("m").WithOptions(EnableIfEnv("CONDITION_TEST_FLAG"))
+	assert.True(t, m.IsEnabled())
+
+	t.Setenv("CONDITION_TEST_FLAG", "no")
+	assert.False(t, m.IsEnabled())
+
+	t.Log("Given the variable is set by the overrides")
+	var a InterfaceA
+	err := invoke(
+		t,
+		BuildFxWithOverrides(
+			Overrides{Env: map[string]string{"CONDITION_TEST_FLAG": "1"}},
+			NewModule("conditional").AddProviders(NewA).WithOptions(EnableIfEnv("CONDITION_TEST_FLAG")),
+		),
+		fx.Populate(&a),
+	)
+
+	t.Log("	Then the module is enabled although the process env disables it")
+	require.NoError(t, err)
+	assert.Equal(t, "A", a.MethodA())
+}
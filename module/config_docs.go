@@ -0,0 +1,85 @@
+package module
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-modulus/modulus/config"
+)
+
+const configDocsNote = `Generated by the "config docs" command. Do not edit`
+
+// WriteEnvExample writes the .env example with the default values of all env variables grouped by module.
+// Each group starts with the "--- <module name> ---" comment. Values of secrets are empty.
+// A variable read by several modules is written only in the group of the first one.
+func (g *Graph) WriteEnvExample(w io.Writer) error {
+	file := config.ParseEnv([]byte("# " + configDocsNote + ".\n"))
+	for _, m := range g.envVarsByModule() {
+		file.AppendBlankLine()
+		file.AppendComment("--- " + m.Name + " ---")
+		for _, envVar := range m.EnvVars {
+			file.Append(envVar.Key, envVar.Default, envVar.Comment)
+		}
+	}
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// WriteConfigReference writes the Markdown reference of all env variables with a table per module.
+func (g *Graph) WriteConfigReference(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# Configuration reference\n\n")
+	b.WriteString("<!-- " + configDocsNote + ". -->\n")
+	for _, m := range g.envVarsByModule() {
+		fmt.Fprintf(&b, "\n## %s\n\n", m.Name)
+		b.WriteString("| Variable | Default | Description |\n")
+		b.WriteString("|----------|---------|-------------|\n")
+		for _, envVar := range m.EnvVars {
+			defaultValue := ""
+			if envVar.Default != "" {
+				defaultValue = "`" + markdownEscape(envVar.Default) + "`"
+			}
+			comment := markdownEscape(envVar.Comment)
+			if envVar.Secret {
+				comment = strings.TrimSpace("Secret. " + comment)
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s |\n", envVar.Key, defaultValue, comment)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// envVarsByModule returns the modules having env variables. Each variable is kept only in the first module reading it.
+// Disabled conditional modules are included, so the docs are the same in every environment.
+func (g *Graph) envVarsByModule() []GraphModule {
+	all := g.AllModules
+	if all == nil {
+		// The graph is not exported from a module tree, e.g. it is decoded from JSON.
+		all = g.Modules
+	}
+	seen := make(map[string]struct{})
+	var modules []GraphModule
+	for _, m := range all {
+		vars := make([]GraphEnvVar, 0, len(m.EnvVars))
+		for _, envVar := range m.EnvVars {
+			if _, ok := seen[envVar.Key]; ok {
+				continue
+			}
+			seen[envVar.Key] = struct{}{}
+			vars = append(vars, envVar)
+		}
+		if len(vars) == 0 {
+			continue
+		}
+		m.EnvVars = vars
+		modules = append(modules, m)
+	}
+	return modules
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", "\\|", "\r\n", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,93 @@
+package module
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph_ConfigDocs(t *testing.T) {
+	t.Parallel()
+	type LoggerConfig struct {
+		Level string `env:"DOCS_TEST_LEVEL, default=debug" comment:"Minimum level | debug or info"`
+		Name  string `env:"DOCS_TEST_NAME, default=my app"`
+	}
+	type DbConfig struct {
+		Dsn   string `env:"DOCS_TEST_DSN, default=postgres://localhost" secret:"true"`
+		Level string `env:"DOCS_TEST_LEVEL, default=info"`
+		Log   bool   `env:"DOCS_TEST_LOG, default=false" comment:"logger"`
+	}
+	type CacheConfig struct {
+		Size int `env:"DOCS_TEST_CACHE_SIZE, default=100"`
+	}
+	logger := NewModule("logger").InitConfig(LoggerConfig{})
+	db := NewModule("db").InitConfig(DbConfig{}).AddDependencies(logger)
+	empty := NewModule("empty")
+	cache := NewModule("cache").
+		InitConfig(CacheConfig{}).
+		EnableIf("never", func() bool { return false })
+	graph := NewGraph(logger, db, empty, cache)
+
+	t.Run(
+		"env example", func(t *testing.T) {
+			t.Parallel()
+			t.Log("Given a disabled conditional module and a variable commented with a module name")
+			t.Log("When the .env example is written")
+			t.Log("	Then the disabled module is documented and the variable stays in the section of its module")
+			var b bytes.Buffer
+
+			err := graph.WriteEnvExample(&b)
+
+			require.NoError(t, err)
+			assert.Equal(
+				t, `# Generated by the "config docs" command. Do not edit.
+
+# --- logger ---
+# Minimum level | debug or info
+DOCS_TEST_LEVEL=debug
+DOCS_TEST_NAME="my app"
+
+# --- db ---
+DOCS_TEST_DSN=
+# logger
+DOCS_TEST_LOG=false
+
+# --- cache ---
+DOCS_TEST_CACHE_SIZE=100
+`, b.String(),
+			)
+		},
+	)
+
+	t.Run(
+		"markdown reference", func(t *testing.T) {
+			t.Parallel()
+			var b bytes.Buffer
+
+			err := graph.WriteConfigReference(&b)
+
+			require.NoError(t, err)
+			assert.Equal(
+				t, "# Configuration reference\n\n"+
+					"<!-- Generated by the \"config docs\" command. Do not edit. -->\n\n"+
+					"## logger\n\n"+
+					"| Variable | Default | Description |\n"+
+					"|----------|---------|-------------|\n"+
+					"| `DOCS_TEST_LEVEL` | `debug` | Minimum level \\| debug or info |\n"+
+					"| `DOCS_TEST_NAME` | `my app` |  |\n"+
+					"\n## db\n\n"+
+					"| Variable | Default | Description |\n"+
+					"|----------|---------|-------------|\n"+
+					"| `DOCS_TEST_DSN` |  | Secret. |\n"+
+					"| `DOCS_TEST_LOG` | `false` | logger |\n"+
+					"\n## cache\n\n"+
+					"| Variable | Default | Description |\n"+
+					"|----------|---------|-------------|\n"+
+					"| `DOCS_TEST_CACHE_SIZE` | `100` |  |\n",
+				b.String(),
+			)
+		},
+	)
+}
@@ -0,0 +1,91 @@
+package module
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/go-modulus/modulus/errors"
+	"github.com/go-modulus/modulus/errors/erruser"
+	"github.com/go-modulus/modulus/validator"
+)
+
+// ValidateConfigs checks the configs of the enabled modules and their dependencies.
+// A config is invalid if its env variables cannot be read or if its Validate(ctx) method returns an error
+// (see validator.Validatable). All problems are returned as one validation error.
+// Codes of the errors are the config names,
+// or the field codes if Validate returns a validation error itself.
+func ValidateConfigs(ctx context.Context, modules ...*Module) error {
+	return newModuleTree(modules).validateConfigs(ctx)
+}
+
+func (g *moduleTree) validateConfigs(ctx context.Context) error {
+	var errs []error
+	for _, node := range g.nodes {
+		errs = append(errs, node.module.validateConfigs(ctx)...)
+	}
+	return erruser.NewValidationError(errs...)
+}
+
+func (m *Module) validateConfigs(ctx context.Context) []error {
+	names := make([]string, 0, len(m.configs))
+	for name := range m.configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, validateConfig(ctx, name, m.configs[name], m.configErrors[name])...)
+	}
+	return errs
+}
+
+// validateConfig returns the validation errors of the config. processErr is the error of reading the env variables.
+func validateConfig(ctx context.Context, name string, config any, processErr error) []error {
+	if processErr != nil {
+		return []error{erruser.NewWithCause(name, processErr.Error(), processErr)}
+	}
+	validatable, ok := asValidatable(config)
+	if !ok {
+		return nil
+	}
+	if err := validatable.Validate(ctx); err != nil {
+		return configValidationErrors(name, err)
+	}
+	return nil
+}
+
+// asValidatable returns the config as validator.Validatable.
+// The Validate method may be defined both on the config struct and on the pointer to it.
+func asValidatable(config any) (validator.Validatable, bool) {
+	if validatable, ok := config.(validator.Validatable); ok {
+		return validatable, true
+	}
+	ptr := reflect.New(reflect.TypeOf(config))
+	ptr.Elem().Set(reflect.ValueOf(config))
+	validatable, ok := ptr.Interface().(validator.Validatable)
+	return validatable, ok
+}
+
+// configValidationErrors splits the validation error into the errors of single fields.
+func configValidationErrors(name string, err error) []error {
+	if errors.HasTag(err, errors.ValidationErrorTag) {
+		meta := errors.Meta(err)
+		codes := make([]string, 0, len(meta))
+		for code := range meta {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		errs := make([]error, 0, len(codes))
+		for _, code := range codes {
+			errs = append(errs, erruser.New(code, meta[code]))
+		}
+		return errs
+	}
+	hint := errors.Hint(err)
+	if hint == "" {
+		hint = err.Error()
+	}
+	return []error{erruser.NewWithCause(name, hint, err)}
+}
@@ -0,0 +1,121 @@
+package module_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	modErrors "github.com/go-modulus/modulus/errors"
+	"github.com/go-modulus/modulus/errors/erruser"
+	"github.com/go-modulus/modulus/module"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type recaptchaConfig struct {
+	Enabled bool   `env:"CONFIG_VALIDATION_TEST_RECAPTCHA_ENABLED, default=false"`
+	Secret  string `env:"CONFIG_VALIDATION_TEST_RECAPTCHA_SECRET"`
+}
+
+func (c recaptchaConfig) Validate(context.Context) error {
+	if c.Enabled && c.Secret == "" {
+		return erruser.NewValidationError(
+			erruser.New("CONFIG_VALIDATION_TEST_RECAPTCHA_SECRET", "The secret is required if recaptcha is enabled"),
+		)
+	}
+	return nil
+}
+
+type portConfig struct {
+	Port int `env:"CONFIG_VALIDATION_TEST_PORT, default=8080"`
+}
+
+func (c *portConfig) Validate(context.Context) error {
+	if c.Port <= 0 {
+		return errors.New("port must be positive")
+	}
+	return nil
+}
+
+type requiredConfig struct {
+	Dsn string `env:"CONFIG_VALIDATION_TEST_DSN, required"`
+}
+
+func TestValidateConfigs(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"valid configs", func(t *testing.T) {
+			t.Parallel()
+			m := module.NewModule("m").
+				InitConfig(recaptchaConfig{}).
+				InitConfig(portConfig{})
+
+			err := module.ValidateConfigs(context.Background(), m)
+
+			require.NoError(t, err)
+		},
+	)
+
+	t.Run(
+		"aggregate errors of all modules", func(t *testing.T) {
+			t.Parallel()
+			dep := module.NewModule("dep").InitConfig(portConfig{Port: -1})
+			m := module.NewModule("m").
+				AddDependencies(dep).
+				InitConfig(recaptchaConfig{Enabled: true}).
+				InitConfig(requiredConfig{})
+
+			err := module.ValidateConfigs(context.Background(), m)
+
+			require.Error(t, err)
+			assert.True(t, modErrors.HasTag(err, modErrors.ValidationErrorTag))
+			meta := modErrors.Meta(err)
+			assert.Equal(t, "The secret is required if recaptcha is enabled", meta["CONFIG_VALIDATION_TEST_RECAPTCHA_SECRET"])
+			assert.Contains(t, meta["github.com/go-modulus/modulus/module_test.requiredConfig"], "missing required value")
+			assert.Equal(t, "port must be positive", meta["github.com/go-modulus/modulus/module_test.portConfig"])
+		},
+	)
+
+	t.Run(
+		"the last config replaces the invalid one", func(t *testing.T) {
+			t.Parallel()
+			m := module.NewModule("m").
+				InitConfig(requiredConfig{}).
+				InitConfig(requiredConfig{Dsn: "postgres://localhost"})
+
+			err := module.ValidateConfigs(context.Background(), m)
+
+			require.NoError(t, err)
+		},
+	)
+
+	t.Run(
+		"configs of disabled modules are not checked", func(t *testing.T) {
+			t.Parallel()
+			m := module.NewModule("m").
+				InitConfig(requiredConfig{}).
+				EnableIf(
+					"never", func() bool {
+						return false
+					},
+				)
+
+			err := module.ValidateConfigs(context.Background(), module.NewModule("root").AddDependencies(m))
+
+			require.NoError(t, err)
+		},
+	)
+
+	t.Run(
+		"BuildFx fails instead of panicking", func(t *testing.T) {
+			t.Parallel()
+			m := module.NewModule("m").InitConfig(requiredConfig{})
+
+			app := fx.New(module.BuildFx(m), fx.NopLogger)
+
+			require.Error(t, app.Err())
+			assert.True(t, modErrors.HasTag(app.Err(), modErrors.ValidationErrorTag))
+		},
+	)
+}
@@ -0,0 +1,291 @@
+package module
+
+import (
+	"strings"
+
+	"github.com/go-modulus/modulus/errors"
+	"github.com/go-modulus/modulus/errors/errsys"
+)
+
+var ErrDuplicateModule = errsys.New(
+	"duplicate module",
+	"Two different modules are registered with the same name",
+)
+
+var ErrDependencyCycle = errsys.New(
+	"module dependency cycle",
+	"Modules depend on each other in a cycle",
+)
+
+// GraphError describes a problem found in the module dependency graph before the fx application is built.
+// Path is the chain of module names from a root module to the module that caused the problem.
+type GraphError struct {
+	Err          error
+	Path         []string
+	ConflictPath []string
+}
+
+func (e *GraphError) Error() string {
+	msg := e.Err.Error() + ": " + strings.Join(e.Path, " -> ")
+	if len(e.ConflictPath) > 0 {
+		msg += " conflicts with " + strings.Join(e.ConflictPath, " -> ")
+	}
+	return msg
+}
+
+func (e *GraphError) Unwrap() error {
+	return e.Err
+}
+
+// graphNode is a module selected for the fx application together with the path it was reached by.
+type graphNode struct {
+	module *Module
+	path   []string
+	level  int
+}
+
+// moduleTree is the deduplicated module tree in the same breadth-first order that BuildFx uses.
+// The first module found with a given name wins, so root modules take precedence over dependencies.
+// Disabled conditional modules are not included, as well as the dependencies reachable only through them.
+type moduleTree struct {
+	nodes     []*graphNode
+	byName    map[string]*graphNode
+	decisions []moduleDecision
+}
+
+func newModuleTree(modules []*Module) *moduleTree {
+	return buildModuleTree(modules, true)
+}
+
+// newFullModuleTree returns the module tree built as if all conditional modules were enabled.
+// Unlike newModuleTree, it doesn't depend on the environment.
+func newFullModuleTree(modules []*Module) *moduleTree {
+	return buildModuleTree(modules, false)
+}
+
+func buildModuleTree(modules []*Module, checkConditions bool) *moduleTree {
+	g := &moduleTree{
+		byName: make(map[string]*graphNode),
+	}
+	disabled := make(map[string]struct{})
+	level := make([]*graphNode, 0, len(modules))
+	for _, m := range modules {
+		level = append(level, &graphNode{module: m, path: []string{m.name}})
+	}
+	for depth := 0; len(level) > 0; depth++ {
+		next := make([]*graphNode, 0)
+		for _, node := range level {
+			if _, ok := g.byName[node.module.name]; ok {
+				continue
+			}
+			if _, ok := disabled[node.module.name]; ok {
+				continue
+			}
+			if checkConditions && node.module.IsConditional() {
+				enabled, reason := node.module.checkConditions()
+				g.decisions = append(
+					g.decisions, moduleDecision{
+						module:  node.module.name,
+						enabled: enabled,
+						reason:  reason,
+					},
+				)
+				if !enabled {
+					disabled[node.module.name] = struct{}{}
+					continue
+				}
+			}
+			node.level = depth
+			g.nodes = append(g.nodes, node)
+			g.byName[node.module.name] = node
+			for _, dep := range node.module.dependencies {
+				next = append(next, &graphNode{module: dep, path: appendPath(node.path, dep.name)})
+			}
+		}
+		level = next
+	}
+	g.assignLevels()
+
+	return g
+}
+
+// assignLevels moves every module one level deeper than the deepest selected module that depends on it.
+// BuildFx invokes the modules of deeper levels first, so dependencies are invoked before their dependants.
+// The number of passes is limited to stop on a graph with cycles.
+func (g *moduleTree) assignLevels() {
+	for pass := 0; pass < len(g.nodes); pass++ {
+		changed := false
+		for _, node := range g.nodes {
+			for _, dep := range node.module.dependencies {
+				depNode, ok := g.byName[dep.name]
+				if ok && depNode.level <= node.level {
+					depNode.level = node.level + 1
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+// levels returns the selected modules grouped by their depth in the graph.
+func (g *moduleTree) levels() [][]*Module {
+	var result [][]*Module
+	for _, node := range g.nodes {
+		for len(result) <= node.level {
+			result = append(result, nil)
+		}
+		result[node.level] = append(result[node.level], node.module)
+	}
+	return result
+}
+
+// ValidateGraph checks the module tree before it is passed to fx.
+// It reports modules that share a name but are created by different constructors or with different content,
+// and dependency cycles.
+// All problems are joined into a single error, each of them is a *GraphError.
+func ValidateGraph(modules ...*Module) error {
+	var errs []error
+	errs = append(errs, findDuplicates(modules)...)
+	errs = append(errs, findCycles(modules)...)
+
+	return errors.Join(errs...)
+}
+
+func findDuplicates(modules []*Module) []error {
+	var errs []error
+	seen := make(map[string]*graphNode)
+	reported := make(map[string][]*Module)
+	visited := make(map[*Module]struct{})
+
+	queue := make([]*graphNode, 0, len(modules))
+	for _, m := range modules {
+		queue = append(queue, &graphNode{module: m, path: []string{m.name}})
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[node.module]; ok {
+			continue
+		}
+		visited[node.module] = struct{}{}
+
+		if first, ok := seen[node.module.name]; ok {
+			if !isSameModule(first.module, node.module) {
+				if !isReported(reported[node.module.name], node.module) {
+					reported[node.module.name] = append(reported[node.module.name], node.module)
+					errs = append(
+						errs, &GraphError{
+							Err:          ErrDuplicateModule,
+							Path:         node.path,
+							ConflictPath: first.path,
+						},
+					)
+				}
+			}
+		} else {
+			seen[node.module.name] = node
+		}
+		for _, dep := range node.module.dependencies {
+			queue = append(queue, &graphNode{module: dep, path: appendPath(node.path, dep.name)})
+		}
+	}
+
+	return errs
+}
+
+// isSameModule returns true if both instances are the same module, e.g. when the constructor of the module
+// is called in several places. The origin alone is not enough: a helper like newStorage(name string, providers ...any)
+// creates different modules on the same line. So the instances created on the same line must also have
+// the same providers, invokes, commands and dependencies. One of them may have more of them,
+// because options like logger.NewModule(logger.CaptureLogs()) add providers to one instance.
+func isSameModule(a, b *Module) bool {
+	if a == b {
+		return true
+	}
+	if a.origin != b.origin {
+		return false
+	}
+	contentA, contentB := a.content(), b.content()
+	return containsAll(contentA, contentB) || containsAll(contentB, contentA)
+}
+
+// content returns the names of the providers, invokes, commands and dependencies of the module.
+func (m *Module) content() map[string]int {
+	content := make(map[string]int)
+	add := func(kind string, names []string) {
+		for _, name := range names {
+			content[kind+" "+name]++
+		}
+	}
+	add("provider", funcNames(m.providers))
+	add("invoke", funcNames(m.invokes))
+	add("cli command", funcNames(m.cliCommandProviders))
+	for _, dep := range m.dependencies {
+		add("dependency", []string{dep.name})
+	}
+	return content
+}
+
+func containsAll(content, subset map[string]int) bool {
+	for name, count := range subset {
+		if content[name] < count {
+			return false
+		}
+	}
+	return true
+}
+
+// isReported returns true if the same module as m is already reported as a duplicate.
+func isReported(reported []*Module, m *Module) bool {
+	for _, r := range reported {
+		if isSameModule(r, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func findCycles(modules []*Module) []error {
+	var errs []error
+	done := make(map[*Module]struct{})
+	reported := make(map[string]struct{})
+	var stack []string
+
+	var visit func(m *Module)
+	visit = func(m *Module) {
+		for i, name := range stack {
+			if name == m.name {
+				cycle := appendPath(stack[i:], m.name)
+				key := strings.Join(cycle, "\x00")
+				if _, ok := reported[key]; !ok {
+					reported[key] = struct{}{}
+					errs = append(errs, &GraphError{Err: ErrDependencyCycle, Path: cycle})
+				}
+				return
+			}
+		}
+		if _, ok := done[m]; ok {
+			return
+		}
+		stack = append(stack, m.name)
+		for _, dep := range m.dependencies {
+			visit(dep)
+		}
+		stack = stack[:len(stack)-1]
+		done[m] = struct{}{}
+	}
+	for _, m := range modules {
+		visit(m)
+	}
+
+	return errs
+}
+
+func appendPath(path []string, name string) []string {
+	result := make([]string, 0, len(path)+1)
+	result = append(result, path...)
+	return append(result, name)
+}
@@ -0,0 +1,255 @@
+package module
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Graph is a serializable view of the module tree built by BuildFx.
+// It is supplied to the fx container in the "module.graphs" group, so commands can render it.
+type Graph struct {
+	Modules []GraphModule `json:"modules"`
+	// AllModules are the modules of the tree built as if all conditional modules were enabled.
+	// The config docs are generated from them, so the docs don't depend on the environment they are generated in.
+	AllModules []GraphModule `json:"-"`
+}
+
+type GraphModule struct {
+	Name                string              `json:"name"`
+	Level               int                 `json:"level"`
+	Dependencies        []string            `json:"dependencies,omitempty"`
+	Providers           []string            `json:"providers,omitempty"`
+	Invokes             []string            `json:"invokes,omitempty"`
+	CliCommands         []string            `json:"cliCommands,omitempty"`
+	TaggedProviders     map[string][]string `json:"taggedProviders,omitempty"`
+	HiddenTags          []string            `json:"hiddenTags,omitempty"`
+	OverriddenProviders map[string]string   `json:"overriddenProviders,omitempty"`
+	Configs             []string            `json:"configs,omitempty"`
+	EnvVars             []GraphEnvVar       `json:"envVars,omitempty"`
+}
+
+// SecretMask replaces the values of secret variables in the exported graph.
+const SecretMask = "******"
+
+// GraphEnvVar is an env variable read by a config of the module.
+// Value is the value of the config field after InitConfig. Values of secrets are replaced with SecretMask
+// if they are set, defaults of secrets are always empty.
+// Besides the fields with the `secret:"true"` tag, the variables with names like *_PASSWORD or *_TOKEN are secrets.
+type GraphEnvVar struct {
+	Key     string `json:"key"`
+	Default string `json:"default"`
+	Value   string `json:"value"`
+	Comment string `json:"comment,omitempty"`
+	Secret  bool   `json:"secret,omitempty"`
+	// InCode is true if the value is set in the config struct passed to InitConfig, so env variables don't change it.
+	InCode bool `json:"inCode,omitempty"`
+}
+
+// NewGraph returns the module tree in the same order and with the same deduplication as BuildFx.
+func NewGraph(modules ...*Module) *Graph {
+	return exportGraph(newModuleTree(modules), modules)
+}
+
+func exportGraph(tree *moduleTree, modules []*Module) *Graph {
+	graph := tree.export()
+	graph.AllModules = newFullModuleTree(modules).export().Modules
+	return graph
+}
+
+func (g *moduleTree) export() *Graph {
+	result := &Graph{
+		Modules: make([]GraphModule, 0, len(g.nodes)),
+	}
+	for _, node := range g.nodes {
+		m := node.module
+		gm := GraphModule{
+			Name:  m.name,
+			Level: node.level,
+		}
+		for _, dep := range m.dependencies {
+			gm.Dependencies = append(gm.Dependencies, dep.name)
+		}
+		gm.Providers = funcNames(m.providers)
+		gm.Invokes = funcNames(m.invokes)
+		if m.exposeCommands {
+			gm.CliCommands = funcNames(m.cliCommandProviders)
+		}
+		if len(m.taggedProviders) > 0 {
+			gm.TaggedProviders = make(map[string][]string, len(m.taggedProviders))
+			for tag, providers := range m.taggedProviders {
+				gm.TaggedProviders[tag] = funcNames(providers)
+			}
+		}
+		for tag := range m.hiddenTags {
+			gm.HiddenTags = append(gm.HiddenTags, tag)
+		}
+		sort.Strings(gm.HiddenTags)
+		if len(m.overriddenProviders) > 0 {
+			gm.OverriddenProviders = make(map[string]string, len(m.overriddenProviders))
+			for name, provider := range m.overriddenProviders {
+				gm.OverriddenProviders[name] = funcName(provider)
+			}
+		}
+		for name := range m.configs {
+			gm.Configs = append(gm.Configs, name)
+		}
+		sort.Strings(gm.Configs)
+		for _, envVar := range m.envVars {
+			details := m.envDetails[envVar.Key]
+			ev := GraphEnvVar{
+				Key:     envVar.Key,
+				Default: details.defaultValue,
+				Value:   envVar.Value,
+				Comment: envVar.Comment,
+				Secret:  envVar.Secret || isSecretEnvKey(envVar.Key),
+				InCode:  details.inCode,
+			}
+			if ev.Secret {
+				ev.Default = ""
+				if details.isSet {
+					ev.Value = SecretMask
+				}
+			}
+			gm.EnvVars = append(gm.EnvVars, ev)
+		}
+
+		result.Modules = append(result.Modules, gm)
+	}
+
+	return result
+}
+
+// WriteDOT writes the graph in the Graphviz DOT format.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph modules {\n")
+	b.WriteString("\trankdir=TB;\n")
+	b.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+	for _, m := range g.Modules {
+		label := strings.Join(m.labelLines(), "\\l") + "\\l"
+		fmt.Fprintf(&b, "\t%s [label=%s];\n", dotQuote(m.Name), dotQuote(label))
+	}
+	for _, m := range g.Modules {
+		for _, dep := range m.Dependencies {
+			fmt.Fprintf(&b, "\t%s -> %s;\n", dotQuote(m.Name), dotQuote(dep))
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	ids := make(map[string]string, len(g.Modules))
+	for i, m := range g.Modules {
+		ids[m.Name] = fmt.Sprintf("m%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, m := range g.Modules {
+		lines := m.labelLines()
+		for i, line := range lines {
+			lines[i] = mermaidEscape(line)
+		}
+		fmt.Fprintf(&b, "\t%s[\"%s\"]\n", ids[m.Name], strings.Join(lines, "<br/>"))
+	}
+	for _, m := range g.Modules {
+		for _, dep := range m.Dependencies {
+			depID, ok := ids[dep]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&b, "\t%s --> %s\n", ids[m.Name], depID)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (m GraphModule) labelLines() []string {
+	lines := []string{m.Name}
+	section := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		lines = append(lines, title+":")
+		for _, item := range items {
+			lines = append(lines, "  "+item)
+		}
+	}
+	section("providers", m.Providers)
+	section("invokes", m.Invokes)
+	section("cli commands", m.CliCommands)
+	tags := make([]string, 0, len(m.TaggedProviders))
+	for tag := range m.TaggedProviders {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		title := "tagged " + tag
+		if slices.Contains(m.HiddenTags, tag) {
+			title += " (hidden)"
+		}
+		section(title, m.TaggedProviders[tag])
+	}
+	names := make([]string, 0, len(m.OverriddenProviders))
+	for name := range m.OverriddenProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	overridden := make([]string, 0, len(names))
+	for _, name := range names {
+		overridden = append(overridden, name+" = "+m.OverriddenProviders[name])
+	}
+	section("overridden providers", overridden)
+	section("configs", m.Configs)
+
+	return lines
+}
+
+func funcNames(funcs []interface{}) []string {
+	if len(funcs) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(funcs))
+	for _, f := range funcs {
+		names = append(names, funcName(f))
+	}
+	return names
+}
+
+// funcName returns the full name of a constructor function.
+// Annotated constructors and other values are described by their string representation or type.
+func funcName(f interface{}) string {
+	val := reflect.ValueOf(f)
+	if val.Kind() == reflect.Func {
+		if fn := runtime.FuncForPC(val.Pointer()); fn != nil {
+			return fn.Name()
+		}
+	}
+	if s, ok := f.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", f)
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+func mermaidEscape(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	s = strings.ReplaceAll(s, "<", "#lt;")
+	s = strings.ReplaceAll(s, ">", "#gt;")
+	return s
+}
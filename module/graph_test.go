@@ -0,0 +1,215 @@
+package module
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-modulus/modulus/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+func TestValidateGraph(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"same module constructed several times is valid", func(t *testing.T) {
+			t.Parallel()
+			m1 := func() *Module {
+				return NewModule("m1")
+			}
+			m2 := NewModule("m2").AddDependencies(m1())
+			m3 := NewModule("m3").AddDependencies(m1(), m2)
+
+			err := ValidateGraph(m1(), m2, m3)
+
+			require.NoError(t, err)
+		},
+	)
+
+	t.Run(
+		"different modules with the same name", func(t *testing.T) {
+			t.Parallel()
+			first := NewModule("storage")
+			second := NewModule("storage")
+			m2 := NewModule("m2").AddDependencies(second)
+
+			err := ValidateGraph(first, m2)
+
+			var graphErr *GraphError
+			require.ErrorAs(t, err, &graphErr)
+			assert.True(t, errors.Is(err, ErrDuplicateModule))
+			assert.Equal(t, []string{"m2", "storage"}, graphErr.Path)
+			assert.Equal(t, []string{"storage"}, graphErr.ConflictPath)
+			assert.Equal(t, "duplicate module: m2 -> storage conflicts with storage", graphErr.Error())
+		},
+	)
+
+	t.Run(
+		"different modules with the same name created by one helper", func(t *testing.T) {
+			t.Parallel()
+			newStorage := func(name string, providers ...any) *Module {
+				return NewModule(name).AddProviders(providers...)
+			}
+			first := newStorage("storage", NewA)
+			second := newStorage("storage", NewAObj)
+			m2 := NewModule("m2").AddDependencies(second)
+
+			err := ValidateGraph(first, m2, newStorage("storage", NewA))
+
+			t.Log("When modules with the same name and origin have different providers")
+			t.Log("	Then the module is reported as a duplicate instead of being dropped")
+			var graphErr *GraphError
+			require.ErrorAs(t, err, &graphErr)
+			assert.True(t, errors.Is(err, ErrDuplicateModule))
+			assert.Equal(t, []string{"m2", "storage"}, graphErr.Path)
+			assert.Equal(t, []string{"storage"}, graphErr.ConflictPath)
+			assert.Len(t, strings.Split(err.Error(), "\n"), 1)
+		},
+	)
+
+	t.Run(
+		"dependency cycle", func(t *testing.T) {
+			t.Parallel()
+			a := NewModule("a")
+			b := NewModule("b").AddDependencies(a)
+			c := NewModule("c").AddDependencies(b)
+			a.AddDependencies(c)
+
+			err := ValidateGraph(a)
+
+			var graphErr *GraphError
+			require.ErrorAs(t, err, &graphErr)
+			assert.True(t, errors.Is(err, ErrDependencyCycle))
+			assert.Equal(t, []string{"a", "c", "b", "a"}, graphErr.Path)
+		},
+	)
+
+	t.Run(
+		"BuildFx fails before running providers", func(t *testing.T) {
+			t.Parallel()
+			called := false
+			a := NewModule("a").AddInvokes(
+				func() {
+					called = true
+				},
+			)
+			b := NewModule("b").AddDependencies(a)
+			a.AddDependencies(b)
+
+			app := fx.New(BuildFx(a), fx.NopLogger)
+			err := app.Start(context.Background())
+
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrDependencyCycle))
+			assert.False(t, called)
+		},
+	)
+}
+
+func TestNewGraph(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"export modules in build order", func(t *testing.T) {
+			t.Parallel()
+			type Config struct {
+				Host string `env:"GRAPH_TEST_HOST, default=localhost"`
+			}
+			m1 := NewModule("m1").AddProviders(NewA).InitConfig(Config{})
+			m2 := NewModule("m2").
+				AddDependencies(m1).
+				AddInvokes(func(a InterfaceA) {}).
+				SetOverriddenProvider("A", NewAObj).
+				AddTaggedProviders("tag", NewOverrideA).
+				HideTags("tag")
+
+			g := NewGraph(m2)
+
+			require.Len(t, g.Modules, 2)
+			assert.Equal(t, "m2", g.Modules[0].Name)
+			assert.Equal(t, 0, g.Modules[0].Level)
+			assert.Equal(t, []string{"m1"}, g.Modules[0].Dependencies)
+			assert.Len(t, g.Modules[0].Invokes, 1)
+			assert.Equal(t, "github.com/go-modulus/modulus/module.NewAObj", g.Modules[0].OverriddenProviders["A"])
+			assert.Equal(t, []string{"github.com/go-modulus/modulus/module.NewOverrideA"}, g.Modules[0].TaggedProviders["tag"])
+			assert.Equal(t, []string{"tag"}, g.Modules[0].HiddenTags)
+			assert.Equal(t, "m1", g.Modules[1].Name)
+			assert.Equal(t, 1, g.Modules[1].Level)
+			assert.Equal(t, []string{"github.com/go-modulus/modulus/module.NewA"}, g.Modules[1].Providers)
+			assert.Equal(t, []string{"github.com/go-modulus/modulus/module.Config"}, g.Modules[1].Configs)
+		},
+	)
+
+	t.Run(
+		"export env variables with masked secrets", func(t *testing.T) {
+			t.Parallel()
+			type Config struct {
+				Host   string `env:"GRAPH_TEST_ENV_HOST, default=localhost" comment:"Host"`
+				Secret string `env:"GRAPH_TEST_ENV_SECRET, default=secret" secret:"true"`
+				Token  string `env:"GRAPH_TEST_ENV_TOKEN" secret:"true"`
+				// untagged, but looks like a secret by the name
+				Password string `env:"GRAPH_TEST_ENV_PASSWORD, default=pass"`
+			}
+			m := NewModule("m").
+				InitConfig(Config{}).
+				InitConfig(Config{Host: "example.com"})
+
+			g := NewGraph(m)
+
+			require.Len(t, g.Modules, 1)
+			assert.Equal(
+				t,
+				[]GraphEnvVar{
+					{Key: "GRAPH_TEST_ENV_HOST", Default: "localhost", Value: "example.com", Comment: "Host", InCode: true},
+					{Key: "GRAPH_TEST_ENV_PASSWORD", Value: SecretMask, Secret: true},
+					{Key: "GRAPH_TEST_ENV_SECRET", Value: SecretMask, Secret: true},
+					{Key: "GRAPH_TEST_ENV_TOKEN", Secret: true},
+				},
+				g.Modules[0].EnvVars,
+			)
+		},
+	)
+
+	t.Run(
+		"render DOT and Mermaid", func(t *testing.T) {
+			t.Parallel()
+			m1 := NewModule("m1").AddProviders(NewA)
+			m2 := NewModule("m2").AddDependencies(m1)
+			g := NewGraph(m2)
+
+			var dot, mermaid strings.Builder
+			require.NoError(t, g.WriteDOT(&dot))
+			require.NoError(t, g.WriteMermaid(&mermaid))
+
+			assert.Contains(t, dot.String(), `"m2" -> "m1";`)
+			assert.Contains(t, dot.String(), `github.com/go-modulus/modulus/module.NewA`)
+			assert.Contains(t, mermaid.String(), "m0 --> m1")
+			assert.Contains(t, mermaid.String(), `m1["m1<br/>providers:<br/>  github.com/go-modulus/modulus/module.NewA"]`)
+		},
+	)
+
+	t.Run(
+		"BuildFx supplies the graph", func(t *testing.T) {
+			t.Parallel()
+			type params struct {
+				fx.In
+				Graphs []*Graph `group:"module.graphs"`
+			}
+			var graphs []*Graph
+			app := fx.New(
+				BuildFx(NewModule("m1")),
+				fx.NopLogger,
+				fx.Invoke(
+					func(p params) {
+						graphs = p.Graphs
+					},
+				),
+			)
+
+			require.NoError(t, app.Err())
+			require.Len(t, graphs, 1)
+			assert.Equal(t, "m1", graphs[0].Modules[0].Name)
+		},
+	)
+}
@@ -0,0 +1,71 @@
+package module
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"braces.dev/errtrace"
+)
+
+// InstallStateFile is the file in the project root where Install records the files it has created.
+// Uninstall deletes only the recorded files whose content is not changed since the installation.
+const InstallStateFile = ".modulus/installed.json"
+
+// installState is the content of InstallStateFile.
+type installState struct {
+	// Files are the files created by the installer by their path relative to the project root.
+	Files map[string]installedFileState `json:"files"`
+}
+
+type installedFileState struct {
+	Module string `json:"module"`
+	// SHA256 is the hex encoded hash of the content written by the installer.
+	SHA256 string `json:"sha256"`
+}
+
+func readInstallState(projectRoot string) (*installState, error) {
+	state := &installState{Files: map[string]installedFileState{}}
+	content, err := os.ReadFile(filepath.Join(projectRoot, InstallStateFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return nil, errtrace.Wrap(err)
+	}
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, errtrace.Errorf("cannot parse %s: %w", InstallStateFile, err)
+	}
+	if state.Files == nil {
+		state.Files = map[string]installedFileState{}
+	}
+	return state, nil
+}
+
+// write saves the state to the project root. The state file is removed if no files are recorded.
+func (s *installState) write(projectRoot string) error {
+	path := filepath.Join(projectRoot, InstallStateFile)
+	if len(s.Files) == 0 {
+		err := os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return errtrace.Wrap(err)
+	}
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errtrace.Wrap(err)
+	}
+	return errtrace.Wrap(os.WriteFile(path, append(content, '\n'), 0644))
+}
+
+func contentHash(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
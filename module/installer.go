@@ -0,0 +1,421 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/go-modulus/modulus/errors/errsys"
+)
+
+var ErrManifestoNotFound = errsys.New(
+	"manifesto not found",
+	"Manifesto of the module dependency is not found",
+)
+
+var ErrInvalidDestFile = errsys.New(
+	"invalid destination file",
+	"Installed file must be placed inside the project",
+)
+
+// FileFetcher returns the content of a file referenced by InstalledFile.SourceUrl.
+type FileFetcher interface {
+	Fetch(ctx context.Context, sourceUrl string) ([]byte, error)
+}
+
+// ManifestoResolver finds manifestos of the module dependencies by the module name.
+type ManifestoResolver interface {
+	Manifesto(name string) (Manifesto, error)
+}
+
+// CommandRunner runs the post-install commands in the project root.
+type CommandRunner interface {
+	Run(ctx context.Context, projectRoot string, command PostInstallCommand) error
+}
+
+// ManifestoList resolves manifestos from a list, e.g. the one returned by LoadManifestos.
+type ManifestoList []Manifesto
+
+func (l ManifestoList) Manifesto(name string) (Manifesto, error) {
+	for _, m := range l {
+		if m.Name == name {
+			return m, nil
+		}
+	}
+	return Manifesto{}, errtrace.Wrap(ErrManifestoNotFound)
+}
+
+// FSFetcher reads files from a file system, e.g. os.DirFS or embed.FS, to install modules offline.
+// The base URL is cut from the beginning of the source URL to get the path of a file in the file system.
+type FSFetcher struct {
+	fsys    fs.FS
+	baseUrl string
+}
+
+func NewFSFetcher(fsys fs.FS, baseUrl string) *FSFetcher {
+	return &FSFetcher{
+		fsys:    fsys,
+		baseUrl: baseUrl,
+	}
+}
+
+func (f *FSFetcher) Fetch(_ context.Context, sourceUrl string) ([]byte, error) {
+	name, ok := strings.CutPrefix(sourceUrl, f.baseUrl)
+	if !ok {
+		return nil, errtrace.Errorf("source %s is outside of the base URL %s", sourceUrl, f.baseUrl)
+	}
+	name = path.Clean(strings.TrimPrefix(name, "/"))
+	return errtrace.Wrap2(fs.ReadFile(f.fsys, name))
+}
+
+// HTTPFetcher downloads files by their source URL.
+type HTTPFetcher struct {
+	client *http.Client
+}
+
+func NewHTTPFetcher(client *http.Client) *HTTPFetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPFetcher{client: client}
+}
+
+func (f *HTTPFetcher) Fetch(ctx context.Context, sourceUrl string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceUrl, nil)
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errtrace.Errorf("cannot download %s: %s", sourceUrl, resp.Status)
+	}
+	return errtrace.Wrap2(io.ReadAll(resp.Body))
+}
+
+// GoRunCommandRunner runs post-install commands with `go run <cmdPackage> <params...>`.
+type GoRunCommandRunner struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func NewGoRunCommandRunner(stdout, stderr io.Writer) *GoRunCommandRunner {
+	return &GoRunCommandRunner{
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+func (r *GoRunCommandRunner) Run(ctx context.Context, projectRoot string, command PostInstallCommand) error {
+	args := append([]string{"run", command.CmdPackage}, command.Params...)
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = projectRoot
+	cmd.Stdout = r.stdout
+	cmd.Stderr = r.stderr
+	return errtrace.Wrap(cmd.Run())
+}
+
+// FileChange is a change of a project file made by the installer.
+type FileChange struct {
+	Path string
+	Old  []byte
+	New  []byte
+	// Skipped is true if the file is left unchanged. Reason explains why.
+	Skipped bool
+	Reason  string
+}
+
+// InstallReport describes what the installer has done or would do in the dry-run mode.
+type InstallReport struct {
+	// Modules are the names of the installed or uninstalled modules, dependencies go first.
+	Modules  []string
+	Changes  []FileChange
+	Commands []PostInstallCommand
+	DryRun   bool
+}
+
+// WriteDiff writes the report in a diff-like format.
+func (r *InstallReport) WriteDiff(w io.Writer) error {
+	var b strings.Builder
+	if r.DryRun {
+		b.WriteString("# dry run, nothing is changed\n")
+	}
+	fmt.Fprintf(&b, "# modules: %s\n", strings.Join(r.Modules, ", "))
+	for _, change := range r.Changes {
+		if change.Skipped {
+			fmt.Fprintf(&b, "# %s %s, skipped\n", change.Path, change.Reason)
+			continue
+		}
+		oldName := "a/" + change.Path
+		if change.Old == nil {
+			oldName = "/dev/null"
+		}
+		newName := "b/" + change.Path
+		if change.New == nil {
+			newName = "/dev/null"
+		}
+		fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+		for _, line := range diffLines(splitLines(change.Old), splitLines(change.New)) {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+	for _, command := range r.Commands {
+		fmt.Fprintf(&b, "$ go run %s\n", strings.Join(append([]string{command.CmdPackage}, command.Params...), " "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Installer applies installation manifestos of modules and their dependencies to a project.
+type Installer struct {
+	fetcher  FileFetcher
+	resolver ManifestoResolver
+	runner   CommandRunner
+}
+
+func NewInstaller(
+	fetcher FileFetcher,
+	resolver ManifestoResolver,
+	runner CommandRunner,
+) *Installer {
+	return &Installer{
+		fetcher:  fetcher,
+		resolver: resolver,
+		runner:   runner,
+	}
+}
+
+// Install applies the manifesto and the manifestos of its dependencies to the project.
+// Env variables are added to the .env file, files are copied, and post-install commands are run.
+// Existing variables and files are left unchanged.
+// Hashes of the copied files are recorded in InstallStateFile to let Uninstall detect local changes.
+func (i *Installer) Install(ctx context.Context, projectRoot string, manifesto Manifesto) (*InstallReport, error) {
+	return i.install(ctx, projectRoot, manifesto, false)
+}
+
+// DryRun reports the changes Install would make without touching the project.
+func (i *Installer) DryRun(ctx context.Context, projectRoot string, manifesto Manifesto) (*InstallReport, error) {
+	return i.install(ctx, projectRoot, manifesto, true)
+}
+
+func (i *Installer) install(
+	ctx context.Context,
+	projectRoot string,
+	manifesto Manifesto,
+	dryRun bool,
+) (*InstallReport, error) {
+	manifestos, err := i.resolve(manifesto)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &InstallReport{DryRun: dryRun}
+	var envVars []ConfigEnvVariable
+	for _, m := range manifestos {
+		report.Modules = append(report.Modules, m.Name)
+		envVars = append(envVars, m.Install.EnvVars...)
+		report.Commands = append(report.Commands, m.Install.PostInstallCommands...)
+	}
+
+	if len(envVars) > 0 {
+		change, err := i.installEnvVars(projectRoot, envVars, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			report.Changes = append(report.Changes, *change)
+		}
+	}
+	state, err := readInstallState(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range manifestos {
+		for _, file := range m.Install.Files {
+			change, err := i.installFile(ctx, projectRoot, file, dryRun)
+			if err != nil {
+				return nil, err
+			}
+			if change == nil {
+				continue
+			}
+			report.Changes = append(report.Changes, *change)
+			if change.Skipped {
+				continue
+			}
+			state.Files[file.DestFile] = installedFileState{Module: m.Name, SHA256: contentHash(change.New)}
+		}
+	}
+	if dryRun {
+		return report, nil
+	}
+	if err := state.write(projectRoot); err != nil {
+		return nil, err
+	}
+	for _, command := range report.Commands {
+		err := i.runner.Run(ctx, projectRoot, command)
+		if err != nil {
+			return report, errtrace.Errorf("post-install command %s failed: %w", command.CmdPackage, err)
+		}
+	}
+
+	return report, nil
+}
+
+// resolve returns the manifesto with all its transitive dependencies, dependencies go first.
+func (i *Installer) resolve(manifesto Manifesto) ([]Manifesto, error) {
+	var result []Manifesto
+	state := make(map[string]bool)
+
+	var visit func(m Manifesto, path []string) error
+	visit = func(m Manifesto, path []string) error {
+		path = appendPath(path, m.Name)
+		if done, ok := state[m.Name]; ok {
+			if !done {
+				return &GraphError{Err: ErrDependencyCycle, Path: path}
+			}
+			return nil
+		}
+		state[m.Name] = false
+		for _, name := range m.Install.Dependencies {
+			dep, err := i.resolver.Manifesto(name)
+			if err != nil {
+				return errtrace.Errorf("dependency %s of %s: %w", name, m.Name, err)
+			}
+			if err := visit(dep, path); err != nil {
+				return err
+			}
+		}
+		state[m.Name] = true
+		result = append(result, m)
+		return nil
+	}
+
+	return result, visit(manifesto, nil)
+}
+
+func (i *Installer) installEnvVars(projectRoot string, envVars []ConfigEnvVariable, dryRun bool) (*FileChange, error) {
+	envFile := filepath.Join(projectRoot, ".env")
+	old, err := os.ReadFile(envFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, errtrace.Wrap(err)
+	}
+	newContent := appendEnvVariables(old, envVars)
+	if bytes.Equal(old, newContent) {
+		return nil, nil
+	}
+	change := &FileChange{Path: ".env", Old: old, New: newContent}
+	if dryRun {
+		return change, nil
+	}
+	if old == nil {
+		if err := os.WriteFile(envFile, nil, 0644); err != nil {
+			return nil, errtrace.Wrap(err)
+		}
+	}
+	return change, errtrace.Wrap(WriteEnvVariablesToFile(envVars, envFile))
+}
+
+func (i *Installer) installFile(
+	ctx context.Context,
+	projectRoot string,
+	file InstalledFile,
+	dryRun bool,
+) (*FileChange, error) {
+	if !filepath.IsLocal(file.DestFile) {
+		return nil, errtrace.Errorf("%s: %w", file.DestFile, ErrInvalidDestFile)
+	}
+	content, err := i.fetcher.Fetch(ctx, file.SourceUrl)
+	if err != nil {
+		return nil, errtrace.Errorf("cannot fetch %s: %w", file.SourceUrl, err)
+	}
+	dest := filepath.Join(projectRoot, file.DestFile)
+	old, err := os.ReadFile(dest)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, errtrace.Wrap(err)
+	}
+	if old != nil {
+		if bytes.Equal(old, content) {
+			return nil, nil
+		}
+		return &FileChange{
+			Path:    file.DestFile,
+			Old:     old,
+			New:     content,
+			Skipped: true,
+			Reason:  "already exists with another content",
+		}, nil
+	}
+	change := &FileChange{Path: file.DestFile, New: content}
+	if dryRun {
+		return change, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	return change, errtrace.Wrap(os.WriteFile(dest, content, 0644))
+}
+
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+// diffLines returns the changed lines of two texts prefixed with "-" and "+".
+// Unchanged lines are omitted.
+func diffLines(a, b []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var result []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, "-"+a[i])
+			i++
+		default:
+			result = append(result, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result = append(result, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		result = append(result, "+"+b[j])
+	}
+	return result
+}
@@ -0,0 +1,142 @@
+package module_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-modulus/modulus/module"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBaseUrl = "https://raw.githubusercontent.com/go-modulus/modulus/refs/heads/main/"
+
+type commandRecorder struct {
+	commands []module.PostInstallCommand
+}
+
+func (r *commandRecorder) Run(_ context.Context, _ string, command module.PostInstallCommand) error {
+	r.commands = append(r.commands, command)
+	return nil
+}
+
+func newTestInstaller(registry module.ManifestoList) (*module.Installer, *commandRecorder) {
+	fsys := fstest.MapFS{
+		"logger/install/logger.yaml": {Data: []byte("level: debug\n")},
+		"http/install/routes.go":     {Data: []byte("package routes\n")},
+	}
+	runner := &commandRecorder{}
+	return module.NewInstaller(module.NewFSFetcher(fsys, testBaseUrl), registry, runner), runner
+}
+
+func testManifestos() module.ManifestoList {
+	logger := module.Manifesto{Name: "logger", Package: "github.com/go-modulus/modulus/logger", Version: "1.0.0"}
+	logger.Install.
+		AppendEnvVars(module.ConfigEnvVariable{Key: "LOGGER_LEVEL", Value: "debug", Comment: "Log level"}).
+		AppendFiles(module.InstalledFile{SourceUrl: testBaseUrl + "logger/install/logger.yaml", DestFile: "config/logger.yaml"})
+
+	http := module.Manifesto{Name: "http", Package: "github.com/go-modulus/modulus/http", Version: "1.0.0"}
+	http.Install.
+		AppendDependencies("logger").
+		AppendEnvVars(module.ConfigEnvVariable{Key: "HTTP_HOST", Value: "localhost:8001"}).
+		AppendFiles(module.InstalledFile{SourceUrl: testBaseUrl + "http/install/routes.go", DestFile: "internal/http/routes.go"}).
+		AppendPostInstallCommands(module.PostInstallCommand{CmdPackage: "github.com/go-modulus/modulus/http/cmd", Params: []string{"init"}})
+
+	return module.ManifestoList{logger, http}
+}
+
+func TestInstaller_Install(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"install module with dependencies", func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(root, ".env"), []byte("HTTP_HOST=0.0.0.0:80\n"), 0644))
+			registry := testManifestos()
+			installer, runner := newTestInstaller(registry)
+
+			report, err := installer.Install(context.Background(), root, registry[1])
+
+			t.Log("When a module is installed with its dependencies")
+			t.Log("	Then the dependencies are installed first, existing env values are kept and the commands are run")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"logger", "http"}, report.Modules)
+
+			env, err := os.ReadFile(filepath.Join(root, ".env"))
+			require.NoError(t, err)
+			assert.Contains(t, string(env), "HTTP_HOST=0.0.0.0:80\n")
+			assert.Contains(t, string(env), "# Log level\nLOGGER_LEVEL=debug\n")
+			assert.NotContains(t, string(env), "localhost:8001")
+
+			routes, err := os.ReadFile(filepath.Join(root, "internal/http/routes.go"))
+			require.NoError(t, err)
+			assert.Equal(t, "package routes\n", string(routes))
+			assert.FileExists(t, filepath.Join(root, "config/logger.yaml"))
+
+			require.Len(t, runner.commands, 1)
+			assert.Equal(t, "github.com/go-modulus/modulus/http/cmd", runner.commands[0].CmdPackage)
+		},
+	)
+
+	t.Run(
+		"dry run reports changes without applying them", func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(root, "config"), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(root, "config/logger.yaml"), []byte("level: info\n"), 0644))
+			registry := testManifestos()
+			installer, runner := newTestInstaller(registry)
+
+			report, err := installer.DryRun(context.Background(), root, registry[1])
+
+			t.Log("When the installation is run in the dry mode")
+			t.Log("	Then the changes are reported as a diff and nothing is written")
+			require.NoError(t, err)
+			assert.Empty(t, runner.commands)
+			assert.NoFileExists(t, filepath.Join(root, ".env"))
+			assert.NoFileExists(t, filepath.Join(root, "internal/http/routes.go"))
+
+			var diff strings.Builder
+			require.NoError(t, report.WriteDiff(&diff))
+			assert.Contains(t, diff.String(), "--- /dev/null\n+++ b/.env\n")
+			assert.Contains(t, diff.String(), "+LOGGER_LEVEL=debug\n")
+			assert.Contains(t, diff.String(), "+HTTP_HOST=localhost:8001\n")
+			assert.Contains(t, diff.String(), "--- /dev/null\n+++ b/internal/http/routes.go\n+package routes\n")
+			assert.Contains(t, diff.String(), "# config/logger.yaml already exists with another content, skipped\n")
+			assert.Contains(t, diff.String(), "$ go run github.com/go-modulus/modulus/http/cmd init\n")
+		},
+	)
+
+	t.Run(
+		"unknown dependency", func(t *testing.T) {
+			t.Parallel()
+			registry := testManifestos()
+			installer, _ := newTestInstaller(registry[1:])
+
+			_, err := installer.DryRun(context.Background(), t.TempDir(), registry[1])
+
+			t.Log("When a dependency is not in the registry")
+			t.Log("	Then the installation fails")
+			require.ErrorIs(t, err, module.ErrManifestoNotFound)
+		},
+	)
+
+	t.Run(
+		"destination outside of the project", func(t *testing.T) {
+			t.Parallel()
+			m := module.Manifesto{Name: "bad", Package: "bad", Version: "1.0.0"}
+			m.Install.AppendFiles(module.InstalledFile{SourceUrl: testBaseUrl + "http/install/routes.go", DestFile: "../routes.go"})
+			installer, _ := newTestInstaller(nil)
+
+			_, err := installer.Install(context.Background(), t.TempDir(), m)
+
+			t.Log("When a file is installed outside of the project")
+			t.Log("	Then the installation fails")
+			require.ErrorIs(t, err, module.ErrInvalidDestFile)
+		},
+	)
+}
@@ -0,0 +1,214 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// DefaultHookTimeout is the time given to each start or stop hook of a module unless SetHookTimeout is called.
+const DefaultHookTimeout = 15 * time.Second
+
+const (
+	HookStageStart = "start"
+	HookStageStop  = "stop"
+)
+
+// HookError is returned when a start or stop hook of a module fails or exceeds its timeout.
+type HookError struct {
+	Module string
+	Stage  string
+	Err    error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("module %s: %s hook failed: %s", e.Module, e.Stage, e.Err.Error())
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// OnStart registers a hook that is called when the application starts.
+// The hook is a function with context.Context as the first parameter, other parameters are resolved from the container.
+// It may return an error. For example:
+//
+//	m.OnStart(func(ctx context.Context, db *pgxpool.Pool) error { return db.Ping(ctx) })
+//
+// Hooks of dependencies are started before the hooks of the modules that depend on them,
+// including the fx hooks appended by their providers and invokes.
+func (m *Module) OnStart(hook interface{}) *Module {
+	m.startHooks = append(m.startHooks, checkHook(m.name, hook))
+	return m
+}
+
+// OnStop registers a hook that is called when the application stops.
+// It has the same signature as the OnStart hook.
+// Hooks of dependencies are stopped after the hooks of the modules that depend on them.
+func (m *Module) OnStop(hook interface{}) *Module {
+	m.stopHooks = append(m.stopHooks, checkHook(m.name, hook))
+	return m
+}
+
+// SetHookTimeout sets the time given to each start and stop hook of the module.
+// A hook that exceeds the timeout fails with context.DeadlineExceeded, but keeps running until it returns.
+func (m *Module) SetHookTimeout(timeout time.Duration) *Module {
+	m.hookTimeout = timeout
+	return m
+}
+
+func checkHook(moduleName string, hook interface{}) interface{} {
+	t := reflect.TypeOf(hook)
+	if t == nil || t.Kind() != reflect.Func {
+		panic(fmt.Sprintf("module %s: hook must be a function, got %T", moduleName, hook))
+	}
+	if t.NumIn() == 0 || t.In(0) != contextType {
+		panic(fmt.Sprintf("module %s: the first parameter of the hook must be context.Context", moduleName))
+	}
+	if t.NumOut() > 1 || (t.NumOut() == 1 && t.Out(0) != errorType) {
+		panic(fmt.Sprintf("module %s: hook may return only an error", moduleName))
+	}
+	return hook
+}
+
+type hookFunc func(ctx context.Context) error
+
+// moduleHooks are the hooks of one module with the resolved dependencies.
+// They are appended to fx.Lifecycle as one fx hook from the fx module of the module.
+type moduleHooks struct {
+	module  string
+	timeout time.Duration
+	start   []hookFunc
+	stop    []hookFunc
+}
+
+// hookInvokes returns fx invokes that resolve the dependencies of the module hooks
+// and append them to fx.Lifecycle. The modules are invoked level by level starting from the dependencies,
+// so the hooks of dependencies run before the hooks of the modules that depend on them,
+// including fx hooks appended by their providers and invokes.
+func (m *Module) hookInvokes() []interface{} {
+	if len(m.startHooks) == 0 && len(m.stopHooks) == 0 {
+		return nil
+	}
+	mh := &moduleHooks{
+		module:  m.name,
+		timeout: m.hookTimeout,
+	}
+	if mh.timeout <= 0 {
+		mh.timeout = DefaultHookTimeout
+	}
+	invokes := make([]interface{}, 0, len(m.startHooks)+len(m.stopHooks)+1)
+	for _, hook := range m.startHooks {
+		invokes = append(
+			invokes, bindHook(
+				hook, func(fn hookFunc) {
+					mh.start = append(mh.start, fn)
+				},
+			),
+		)
+	}
+	for _, hook := range m.stopHooks {
+		invokes = append(
+			invokes, bindHook(
+				hook, func(fn hookFunc) {
+					mh.stop = append(mh.stop, fn)
+				},
+			),
+		)
+	}
+	invokes = append(
+		invokes, func(lc fx.Lifecycle) {
+			lc.Append(
+				fx.Hook{
+					OnStart: mh.onStart,
+					OnStop:  mh.onStop,
+				},
+			)
+		},
+	)
+	return invokes
+}
+
+// bindHook makes an invoke function that takes the hook dependencies from the container
+// and passes the hook with bound dependencies to register.
+func bindHook(hook interface{}, register func(fn hookFunc)) interface{} {
+	hookVal := reflect.ValueOf(hook)
+	hookType := hookVal.Type()
+	in := make([]reflect.Type, 0, hookType.NumIn()-1)
+	for i := 1; i < hookType.NumIn(); i++ {
+		in = append(in, hookType.In(i))
+	}
+	invokeType := reflect.FuncOf(in, nil, false)
+
+	return reflect.MakeFunc(
+		invokeType, func(args []reflect.Value) []reflect.Value {
+			register(
+				func(ctx context.Context) error {
+					callArgs := make([]reflect.Value, 0, len(args)+1)
+					callArgs = append(callArgs, reflect.ValueOf(&ctx).Elem())
+					callArgs = append(callArgs, args...)
+					out := hookVal.Call(callArgs)
+					if len(out) == 0 || out[0].IsNil() {
+						return nil
+					}
+					return out[0].Interface().(error)
+				},
+			)
+			return nil
+		},
+	).Interface()
+}
+
+// onStart runs the start hooks of the module.
+// If a hook fails, fx stops the hooks that are already started, the stop hooks of this module are not called.
+func (h *moduleHooks) onStart(ctx context.Context) error {
+	for _, fn := range h.start {
+		if err := runHook(ctx, h.timeout, fn); err != nil {
+			return &HookError{Module: h.module, Stage: HookStageStart, Err: err}
+		}
+	}
+	return nil
+}
+
+// onStop runs the stop hooks of the module. All hooks are called even if some of them fail.
+// The first error is returned.
+// The hooks don't inherit the cancellation of ctx. When a start fails, fx stops the started hooks
+// with the start context that may be already expired, so each stop hook gets its own timeout.
+func (h *moduleHooks) onStop(ctx context.Context) error {
+	ctx = context.WithoutCancel(ctx)
+	var firstErr error
+	for _, fn := range h.stop {
+		if err := runHook(ctx, h.timeout, fn); err != nil && firstErr == nil {
+			firstErr = &HookError{Module: h.module, Stage: HookStageStop, Err: err}
+		}
+	}
+	return firstErr
+}
+
+// runHook calls the hook with the timeout.
+// Go cannot stop a goroutine, so a hook that ignores the context and exceeds the timeout keeps running
+// in the background after the timeout error is returned.
+func runHook(ctx context.Context, timeout time.Duration, fn hookFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,238 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type hookRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *hookRecorder) record(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func TestModule_OnStart(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"dependencies start first and stop last", func(t *testing.T) {
+			t.Parallel()
+			rec := &hookRecorder{}
+			hooks := func(m *Module) *Module {
+				return m.
+					OnStart(
+						func(ctx context.Context) error {
+							rec.record("start " + m.name)
+							return nil
+						},
+					).
+					OnStop(
+						func(ctx context.Context) {
+							rec.record("stop " + m.name)
+						},
+					)
+			}
+			m1 := hooks(NewModule("m1"))
+			m2 := hooks(NewModule("m2").AddDependencies(m1))
+			m3 := hooks(NewModule("m3").AddDependencies(m2))
+
+			// m3 and m2 are both root modules, but m3 depends on m2
+			app := fx.New(BuildFx(m3, m2), fx.NopLogger)
+			require.NoError(t, app.Start(context.Background()))
+			require.NoError(t, app.Stop(context.Background()))
+
+			t.Log("When the app with the module hooks is started and stopped")
+			t.Log("	Then the dependencies start first and stop last")
+			assert.Equal(
+				t,
+				[]string{"start m1", "start m2", "start m3", "stop m3", "stop m2", "stop m1"},
+				rec.calls,
+			)
+		},
+	)
+
+	t.Run(
+		"hook dependencies are resolved from the container", func(t *testing.T) {
+			t.Parallel()
+			var got InterfaceA
+			m := NewModule("m").
+				AddProviders(NewA).
+				OnStart(
+					func(ctx context.Context, a InterfaceA) error {
+						got = a
+						return nil
+					},
+				)
+
+			app := fx.New(BuildFx(m), fx.NopLogger)
+			require.NoError(t, app.Start(context.Background()))
+
+			t.Log("When the start hook has parameters")
+			t.Log("	Then they are resolved from the container")
+			require.NotNil(t, got)
+			assert.Equal(t, "A", got.MethodA())
+		},
+	)
+
+	t.Run(
+		"failed hook reports the module and stops started modules", func(t *testing.T) {
+			t.Parallel()
+			rec := &hookRecorder{}
+			errFailed := errors.New("failed")
+			m1 := NewModule("m1").
+				OnStop(
+					func(ctx context.Context) {
+						rec.record("stop m1")
+					},
+				)
+			m2 := NewModule("m2").
+				AddDependencies(m1).
+				OnStart(
+					func(ctx context.Context) error {
+						return errFailed
+					},
+				).
+				OnStop(
+					func(ctx context.Context) {
+						rec.record("stop m2")
+					},
+				)
+
+			app := fx.New(BuildFx(m2), fx.NopLogger)
+			err := app.Start(context.Background())
+
+			t.Log("When the start hook of a module fails")
+			t.Log("	Then the error names the module and the started modules are stopped")
+			var hookErr *HookError
+			require.ErrorAs(t, err, &hookErr)
+			assert.Equal(t, "m2", hookErr.Module)
+			assert.Equal(t, HookStageStart, hookErr.Stage)
+			assert.ErrorIs(t, err, errFailed)
+			assert.Equal(t, []string{"stop m1"}, rec.calls)
+		},
+	)
+
+	t.Run(
+		"dependencies start before fx hooks of dependants", func(t *testing.T) {
+			t.Parallel()
+			rec := &hookRecorder{}
+			db := NewModule("db").
+				OnStart(
+					func(ctx context.Context) error {
+						rec.record("start db")
+						return nil
+					},
+				)
+			server := NewModule("server").
+				AddDependencies(db).
+				AddInvokes(
+					func(lc fx.Lifecycle) {
+						lc.Append(
+							fx.Hook{
+								OnStart: func(ctx context.Context) error {
+									rec.record("start server")
+									return nil
+								},
+							},
+						)
+					},
+				)
+			// a root module depends on a module reached through another root first
+			app := NewModule("app").AddDependencies(server)
+
+			fxApp := fx.New(BuildFx(app, db), fx.NopLogger)
+			require.NoError(t, fxApp.Start(context.Background()))
+
+			t.Log("When a dependency has a module start hook and the dependant has an fx hook of an invoke")
+			t.Log("	Then the dependency starts first")
+			assert.Equal(t, []string{"start db", "start server"}, rec.calls)
+		},
+	)
+
+	t.Run(
+		"started modules are stopped after the start context expires", func(t *testing.T) {
+			t.Parallel()
+			var stopErr error
+			m1 := NewModule("m1").
+				OnStop(
+					func(ctx context.Context) {
+						stopErr = ctx.Err()
+					},
+				)
+			m2 := NewModule("m2").
+				AddDependencies(m1).
+				OnStart(
+					func(ctx context.Context) error {
+						<-ctx.Done()
+						return ctx.Err()
+					},
+				)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			app := fx.New(BuildFx(m2), fx.NopLogger)
+			require.Error(t, app.Start(ctx))
+			stopCtx, cancelStop := context.WithTimeout(context.Background(), time.Second)
+			defer cancelStop()
+			require.NoError(t, app.Stop(stopCtx))
+
+			t.Log("When the start context expires while a hook is running")
+			t.Log("	Then the started modules are stopped with a context that is not expired")
+			assert.NoError(t, stopErr)
+		},
+	)
+
+	t.Run(
+		"hook timeout", func(t *testing.T) {
+			t.Parallel()
+			m := NewModule("slow").
+				SetHookTimeout(10 * time.Millisecond).
+				OnStart(
+					func(ctx context.Context) error {
+						<-ctx.Done()
+						time.Sleep(50 * time.Millisecond)
+						return nil
+					},
+				)
+
+			app := fx.New(BuildFx(m), fx.NopLogger)
+			err := app.Start(context.Background())
+
+			t.Log("When the start hook runs longer than the hook timeout")
+			t.Log("	Then the start fails with the deadline error of the module")
+			var hookErr *HookError
+			require.ErrorAs(t, err, &hookErr)
+			assert.Equal(t, "slow", hookErr.Module)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		},
+	)
+
+	t.Run(
+		"invalid hook signature", func(t *testing.T) {
+			t.Parallel()
+			t.Log("When a hook without a context or with an unsupported result is added")
+			t.Log("	Then the module panics")
+			assert.Panics(
+				t, func() {
+					NewModule("m").OnStart(func(a InterfaceA) {})
+				},
+			)
+			assert.Panics(
+				t, func() {
+					NewModule("m").OnStop(func(ctx context.Context) string { return "" })
+				},
+			)
+		},
+	)
+}
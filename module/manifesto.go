@@ -20,6 +20,8 @@ func NewManifesto(
 	description string,
 	version string,
 ) Manifesto {
+	// conditions are checked at runtime, so conditional dependencies and env variables are listed
+	// regardless of the environment the manifesto is generated in
 	deps := make([]string, 0, len(module.dependencies))
 	for _, dep := range module.dependencies {
 		deps = append(deps, dep.name)
@@ -0,0 +1,189 @@
+package module
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/go-modulus/modulus/errors/erruser"
+)
+
+//go:embed manifesto.schema.json
+var manifestoSchema []byte
+
+var semverRegexp = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+var packageRegexp = regexp.MustCompile(`^[A-Za-z0-9_~+-][A-Za-z0-9._~+-]*(/[A-Za-z0-9_~+-][A-Za-z0-9._~+-]*)*$`)
+
+// ManifestoSchema returns the JSON schema of the module registry file read by LoadManifestos.
+func ManifestoSchema() []byte {
+	return manifestoSchema
+}
+
+// Registry is the content of a module registry file.
+type Registry struct {
+	Modules []Manifesto `json:"modules"`
+}
+
+// LoadManifesto reads a single manifesto from the JSON file and validates it.
+// Dependencies are not checked because other manifestos are unknown.
+func LoadManifesto(path string) (Manifesto, error) {
+	var manifesto Manifesto
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return manifesto, errtrace.Wrap(err)
+	}
+	err = json.Unmarshal(content, &manifesto)
+	if err != nil {
+		return manifesto, errtrace.Errorf("cannot parse manifesto %s: %w", path, err)
+	}
+
+	return manifesto, ValidateManifesto(manifesto)
+}
+
+// LoadManifestos reads the module registry from the JSON file and validates all manifestos in it.
+// The file contains either an object with the "modules" list or the list of manifestos itself.
+// Validation errors are reported with JSON pointers to the invalid values.
+func LoadManifestos(path string) ([]Manifesto, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+
+	prefix := "/modules"
+	var registry Registry
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '[' {
+		prefix = ""
+		err = json.Unmarshal(content, &registry.Modules)
+	} else {
+		err = json.Unmarshal(content, &registry)
+	}
+	if err != nil {
+		return nil, errtrace.Errorf("cannot parse manifestos %s: %w", path, err)
+	}
+
+	return registry.Modules, validateManifestos(registry.Modules, prefix)
+}
+
+// ValidateManifesto checks a single manifesto.
+// The returned error is a validation error with a JSON pointer as the code of each invalid field.
+func ValidateManifesto(manifesto Manifesto) error {
+	return erruser.NewValidationError(validateManifesto(manifesto, "", nil)...)
+}
+
+// ValidateManifestos checks the list of manifestos including the dependencies between them.
+// The returned error is a validation error with a JSON pointer as the code of each invalid field.
+func ValidateManifestos(manifestos []Manifesto) error {
+	return validateManifestos(manifestos, "")
+}
+
+func validateManifestos(manifestos []Manifesto, prefix string) error {
+	known := make(map[string]int, len(manifestos))
+	var errs []error
+	for i, manifesto := range manifestos {
+		if first, ok := known[manifesto.Name]; ok && manifesto.Name != "" {
+			errs = append(
+				errs, manifestoError(
+					pointer(prefix, i, "name"),
+					fmt.Sprintf("Module %s is already defined at %s", manifesto.Name, pointer(prefix, first)),
+				),
+			)
+			continue
+		}
+		known[manifesto.Name] = i
+	}
+	for i, manifesto := range manifestos {
+		errs = append(errs, validateManifesto(manifesto, pointer(prefix, i), known)...)
+	}
+
+	return erruser.NewValidationError(errs...)
+}
+
+// validateManifesto returns errors of a manifesto located at the path.
+// If known is nil, dependencies are not checked.
+func validateManifesto(manifesto Manifesto, path string, known map[string]int) []error {
+	var errs []error
+	if strings.TrimSpace(manifesto.Name) == "" {
+		errs = append(errs, manifestoError(pointer(path, "name"), "Name is required"))
+	}
+	if !packageRegexp.MatchString(manifesto.Package) || strings.Contains(manifesto.Package, "..") {
+		errs = append(errs, manifestoError(pointer(path, "package"), "Package must be a valid Go import path"))
+	}
+	if !semverRegexp.MatchString(manifesto.Version) {
+		errs = append(errs, manifestoError(pointer(path, "version"), "Version must be a semantic version like 1.2.3"))
+	}
+
+	install := pointer(path, "install")
+	keys := make(map[string]int, len(manifesto.Install.EnvVars))
+	for i, envVar := range manifesto.Install.EnvVars {
+		keyPath := pointer(install, "envVars", i, "key")
+		if strings.TrimSpace(envVar.Key) == "" {
+			errs = append(errs, manifestoError(keyPath, "Env variable key is required"))
+			continue
+		}
+		if first, ok := keys[envVar.Key]; ok {
+			errs = append(
+				errs, manifestoError(
+					keyPath,
+					fmt.Sprintf("Env variable %s is already defined at %s", envVar.Key, pointer(install, "envVars", first)),
+				),
+			)
+			continue
+		}
+		keys[envVar.Key] = i
+	}
+	for i, dep := range manifesto.Install.Dependencies {
+		depPath := pointer(install, "dependencies", i)
+		if strings.TrimSpace(dep) == "" {
+			errs = append(errs, manifestoError(depPath, "Dependency name is required"))
+			continue
+		}
+		if known == nil {
+			continue
+		}
+		if _, ok := known[dep]; !ok {
+			errs = append(errs, manifestoError(depPath, fmt.Sprintf("Unknown dependency %s", dep)))
+		}
+	}
+	for i, file := range manifesto.Install.Files {
+		if file.SourceUrl == "" {
+			errs = append(errs, manifestoError(pointer(install, "files", i, "sourceUrl"), "Source URL is required"))
+		}
+		if file.DestFile == "" {
+			errs = append(errs, manifestoError(pointer(install, "files", i, "destFile"), "Destination file is required"))
+		}
+	}
+	for i, command := range manifesto.Install.PostInstallCommands {
+		if command.CmdPackage == "" {
+			errs = append(
+				errs,
+				manifestoError(pointer(install, "postInstallCommands", i, "cmdPackage"), "Command package is required"),
+			)
+		}
+	}
+
+	return errs
+}
+
+func manifestoError(pointer string, message string) error {
+	return erruser.New(pointer, message)
+}
+
+// pointer builds a JSON pointer (RFC 6901) by appending the tokens to the base pointer.
+func pointer(base string, tokens ...any) string {
+	var b strings.Builder
+	b.WriteString(base)
+	for _, token := range tokens {
+		b.WriteString("/")
+		t := fmt.Sprint(token)
+		t = strings.ReplaceAll(t, "~", "~0")
+		t = strings.ReplaceAll(t, "/", "~1")
+		b.WriteString(t)
+	}
+	return b.String()
+}
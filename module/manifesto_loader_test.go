@@ -0,0 +1,148 @@
+package module_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-modulus/modulus/errors"
+	"github.com/go-modulus/modulus/module"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "modules.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadManifestos(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"load valid registry", func(t *testing.T) {
+			t.Parallel()
+			path := writeFile(
+				t, `{"modules": [
+					{"name": "logger", "package": "github.com/go-modulus/modulus/logger", "version": "1.0.0"},
+					{"name": "http", "package": "github.com/go-modulus/modulus/http", "version": "1.2.0-beta.1",
+					 "install": {"dependencies": ["logger"], "envVars": [{"key": "HTTP_HOST", "value": "localhost:8001"}]}}
+				]}`,
+			)
+
+			manifestos, err := module.LoadManifestos(path)
+
+			t.Log("When a valid registry is loaded")
+			t.Log("	Then all manifestos are returned with their install sections")
+			require.NoError(t, err)
+			require.Len(t, manifestos, 2)
+			assert.Equal(t, "http", manifestos[1].Name)
+			assert.Equal(t, []string{"logger"}, manifestos[1].Install.Dependencies)
+		},
+	)
+
+	t.Run(
+		"report invalid fields with JSON pointers", func(t *testing.T) {
+			t.Parallel()
+			path := writeFile(
+				t, `{"modules": [
+					{"name": "logger", "package": "github.com/go-modulus/modulus/logger", "version": "v1"},
+					{"name": "http", "package": "github.com//http", "version": "1.0.0",
+					 "install": {
+						"dependencies": ["logger", "cli"],
+						"envVars": [{"key": "HTTP_HOST"}, {"key": "HTTP_HOST"}]
+					 }}
+				]}`,
+			)
+
+			_, err := module.LoadManifestos(path)
+
+			t.Log("When a registry with invalid fields is loaded")
+			t.Log("	Then each problem is reported in the meta by the JSON pointer of the field")
+			require.Error(t, err)
+			assert.Equal(t, "invalid input", err.Error())
+			meta := errors.Meta(err)
+			assert.Equal(t, "Version must be a semantic version like 1.2.3", meta["/modules/0/version"])
+			assert.Equal(t, "Package must be a valid Go import path", meta["/modules/1/package"])
+			assert.Equal(t, "Unknown dependency cli", meta["/modules/1/install/dependencies/1"])
+			assert.Equal(
+				t,
+				"Env variable HTTP_HOST is already defined at /modules/1/install/envVars/0",
+				meta["/modules/1/install/envVars/1/key"],
+			)
+			assert.Len(t, meta, 4)
+		},
+	)
+
+	t.Run(
+		"load list of manifestos", func(t *testing.T) {
+			t.Parallel()
+			path := writeFile(
+				t, `[
+					{"name": "logger", "package": "github.com/go-modulus/modulus/logger", "version": "1.0.0"},
+					{"name": "logger", "package": "github.com/go-modulus/modulus/logger", "version": "1.0.0"}
+				]`,
+			)
+
+			_, err := module.LoadManifestos(path)
+
+			t.Log("When a list of manifestos with a duplicate name is loaded")
+			t.Log("	Then the duplicate is reported")
+			require.Error(t, err)
+			assert.Equal(t, "Module logger is already defined at /0", errors.Meta(err)["/1/name"])
+		},
+	)
+
+	t.Run(
+		"invalid JSON", func(t *testing.T) {
+			t.Parallel()
+			path := writeFile(t, `{"modules": [`)
+
+			_, err := module.LoadManifestos(path)
+
+			t.Log("When the file is not valid JSON")
+			t.Log("	Then an error is returned")
+			require.Error(t, err)
+		},
+	)
+}
+
+func TestLoadManifesto(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"load manifesto created by NewManifesto", func(t *testing.T) {
+			t.Parallel()
+			type Config struct {
+				Host string `env:"LOADER_TEST_HOST, default=localhost"`
+			}
+			expected := module.NewManifesto(
+				module.NewModule("test").InitConfig(Config{}),
+				"github.com/go-modulus/modulus/test",
+				"Test module",
+				"1.0.0",
+			)
+			content, err := json.Marshal(expected)
+			require.NoError(t, err)
+			path := writeFile(t, string(content))
+
+			manifesto, err := module.LoadManifesto(path)
+
+			t.Log("When the JSON of a manifesto created by NewManifesto is loaded")
+			t.Log("	Then it equals the original manifesto")
+			require.NoError(t, err)
+			assert.Equal(t, expected, manifesto)
+		},
+	)
+}
+
+func TestManifestoSchema(t *testing.T) {
+	t.Parallel()
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(module.ManifestoSchema(), &schema))
+
+	t.Log("When the schema of the manifestos is decoded")
+	t.Log("	Then it is a valid JSON schema with definitions")
+	assert.Contains(t, schema, "$defs")
+}
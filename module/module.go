@@ -2,10 +2,15 @@ package module
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"runtime"
+	"slices"
 	"sort"
+	"time"
 
+	"github.com/go-modulus/modulus/config"
 	"github.com/sethvargo/go-envconfig"
 	"go.uber.org/fx"
 )
@@ -16,22 +21,46 @@ type Module struct {
 	providers           []interface{}
 	invokes             []interface{}
 	configs             map[string]interface{}
+	configErrors        map[string]error
 	name                string
 	envVars             []ConfigEnvVariable
+	envDetails          map[string]configVariable
 	fxOptions           []fx.Option
 	taggedProviders     map[string][]interface{}
 	overriddenProviders map[string]interface{}
 	decorators          []interface{}
+	startHooks          []interface{}
+	stopHooks           []interface{}
+	hookTimeout         time.Duration
+	conditions          []condition
 
 	exposeCommands bool
 	hiddenTags     map[string]struct{}
+	// origin is the source line that created the module. Instances with the same name and origin are the same module
+	// if their content matches, see isSameModule.
+	origin string
+	// configInitials are the config structs passed to InitConfig before reading the env variables.
+	configInitials map[string]interface{}
+	// reloadables create the *Reloadable values of the configs registered by InitReloadableConfig.
+	reloadables map[string]func(initial, value any, env map[string]string) reloader
+	// env overrides the process environment for the configs of the module. It is set by Overrides.
+	env map[string]string
 }
 
 func NewModule(name string) *Module {
+	origin := ""
+	if _, file, line, ok := runtime.Caller(1); ok {
+		origin = fmt.Sprintf("%s:%d", file, line)
+	}
 	return &Module{
 		name:           name,
 		exposeCommands: true,
 		configs:        make(map[string]interface{}),
+		configErrors:   make(map[string]error),
+		configInitials: make(map[string]interface{}),
+		reloadables:    make(map[string]func(initial, value any, env map[string]string) reloader),
+		envDetails:     make(map[string]configVariable),
+		origin:         origin,
 	}
 }
 
@@ -102,7 +131,7 @@ func (m *Module) HideTags(tags ...string) *Module {
 	return m
 }
 
-func (m *Module) buildFx() fx.Option {
+func (m *Module) buildFx(watcher *configWatcher) fx.Option {
 	opts := make([]fx.Option, 0, 2+len(m.dependencies))
 	providers := make([]interface{}, 0, len(m.providers)+len(m.cliCommandProviders))
 	providers = append(providers, m.providers...)
@@ -132,12 +161,18 @@ func (m *Module) buildFx() fx.Option {
 		for _, config := range m.configs {
 			supplies = append(supplies, config)
 		}
+		supplies = append(supplies, watcher.supplies(m)...)
 		opts = append(opts, fx.Supply(supplies...))
 	}
 	if len(m.decorators) > 0 {
 		opts = append(opts, fx.Decorate(m.decorators...))
 	}
 
+	// the hooks are appended before the invokes to start the module before the fx hooks of its invokes
+	if hookInvokes := m.hookInvokes(); len(hookInvokes) > 0 {
+		opts = append(opts, fx.Invoke(hookInvokes...))
+	}
+
 	if len(m.invokes) > 0 {
 		opts = append(opts, fx.Invoke(m.invokes...))
 	}
@@ -167,6 +202,11 @@ func (m *Module) provideCommand(command interface{}) interface{} {
 // The last value added before the BuildFx() call will be used.
 // Note: After the BuildFx() call, the config struct will be immutable.
 // Note: Passed values of a struct have the highest priority. Env variables can overwrite only default values.
+// Note: Values of the fields with the `secret:"true"` tag are resolved by config.ResolveSecret,
+// e.g. "file:///run/secrets/db_password" is replaced with the content of the file.
+// These values are never exposed by the module: they are empty in the manifesto and masked in the graph.
+// Note: Errors of reading env variables and of the Validate(ctx) method of the config are returned by ValidateConfigs
+// and make the BuildFx() options fail.
 func (m *Module) InitConfig(config any) *Module {
 	val := reflect.ValueOf(config)
 	if val.Kind() != reflect.Ptr {
@@ -175,23 +215,36 @@ func (m *Module) InitConfig(config any) *Module {
 		config = vp.Interface()
 	}
 
-	err := envconfig.Process(context.Background(), config)
-	if err != nil {
-		panic(err)
-	}
+	initial := reflect.ValueOf(config).Elem().Interface()
+	err := processConfig(context.Background(), config, m.env)
 
 	val = reflect.ValueOf(config)
 
 	filledConfig := val.Elem().Interface()
 	name := m.getConfigName(filledConfig)
 	m.configs[name] = filledConfig
+	m.configInitials[name] = initial
+	if err != nil {
+		m.configErrors[name] = err
+	} else {
+		delete(m.configErrors, name)
+	}
 
 	vars := getVariables(config, false)
+	initialVars := getVariables(initial, false)
 	for _, value := range vars {
 		if value.Key == "" {
 			continue
 		}
-		m.envVars = append(m.envVars, value)
+		value.inCode = initialVars[value.Key].isSet
+		// the config can be initialized several times, the last value wins
+		m.envVars = slices.DeleteFunc(
+			m.envVars, func(v ConfigEnvVariable) bool {
+				return v.Key == value.Key
+			},
+		)
+		m.envVars = append(m.envVars, value.ConfigEnvVariable)
+		m.envDetails[value.Key] = value
 	}
 	if len(m.envVars) > 0 {
 		sort.Slice(
@@ -204,8 +257,25 @@ func (m *Module) InitConfig(config any) *Module {
 	return m
 }
 
+// processConfig fills the config struct by the pointer with the env variables.
+// Variables from env have priority over the process environment.
+// References in the values of secret variables are resolved.
+func processConfig(ctx context.Context, config any, env map[string]string) error {
+	lookuper, secretErr := newSecretLookuper(ctx, getVariables(config, false), env)
+	err := envconfig.ProcessWith(
+		ctx, &envconfig.Config{
+			Target:   config,
+			Lookuper: lookuper,
+		},
+	)
+	return errors.Join(secretErr, err)
+}
+
 func (m *Module) getConfigName(config any) string {
-	t := reflect.TypeOf(config)
+	return configTypeName(reflect.TypeOf(config))
+}
+
+func configTypeName(t reflect.Type) string {
 	pckgPath := t.PkgPath()
 	nameOfType := t.Name()
 
@@ -221,35 +291,66 @@ func (m *Module) WithOptions(opts ...Option) *Module {
 	return m
 }
 
-func BuildFx(modules ...*Module) fx.Option {
-	var builtModules = make(map[string]struct{})
-	return buildFx(modules, builtModules, 0)
+// WithOptionsFor returns the option that applies the given options only in the environment env,
+// e.g. module.WithOptionsFor(config.EnvLocal, logger.SetConfig(...)).
+// The environment is checked when the option is applied, so the env variables must be loaded before.
+func WithOptionsFor(env config.Environment, opts ...Option) Option {
+	return func(m *Module) *Module {
+		if config.CurrentEnvironment() != env {
+			return m
+		}
+		return m.WithOptions(opts...)
+	}
 }
 
-func buildFx(
-	modules []*Module,
-	builtModules map[string]struct{},
-	level int,
-) fx.Option {
-	opts := make([]fx.Option, 0, len(modules))
-	for _, module := range modules {
-		opts = append(opts, module.buildFx())
-		builtModules[module.name] = struct{}{}
+// BuildFx validates the module graph and builds the fx options for all modules and their dependencies.
+// Each module is built only once. If the graph is invalid, the returned option makes fx fail with a *GraphError.
+// If configs of the enabled modules are invalid, it fails with the validation error returned by ValidateConfigs.
+// The built *Graph is supplied to the "module.graphs" group, so several BuildFx options can be used in one app.
+// Add ProvideEnvironment to the app to get config.Environment from the container.
+func BuildFx(modules ...*Module) fx.Option {
+	if err := ValidateGraph(modules...); err != nil {
+		return fx.Error(err)
+	}
+	tree := newModuleTree(modules)
+	if err := tree.validateConfigs(context.Background()); err != nil {
+		return fx.Error(err)
+	}
+	watcher := newConfigWatcher(tree)
+	opts := []fx.Option{
+		fx.Supply(fx.Annotated{Group: "module.graphs", Target: exportGraph(tree, modules)}),
+		buildFx(tree.levels(), watcher, 0),
+	}
+	if len(tree.decisions) > 0 {
+		opts = append(opts, fx.Invoke(logDecisions(tree.decisions)))
+	}
+	if !watcher.isEmpty() {
+		opts = append(opts, fx.Invoke(watcher.invoke))
 	}
 
-	// Add dependencies
-	deps := make([]*Module, 0, len(builtModules))
-	for _, module := range modules {
-		for _, dep := range module.dependencies {
-			if _, ok := builtModules[dep.name]; !ok {
-				deps = append(deps, dep)
-				builtModules[dep.name] = struct{}{}
-			}
-		}
+	return fx.Options(opts...)
+}
+
+// ProvideEnvironment provides config.CurrentEnvironment() as config.Environment.
+// Add it once per app, not per BuildFx call:
+//
+//	fx.New(module.BuildFx(modules...), module.ProvideEnvironment())
+func ProvideEnvironment() fx.Option {
+	return fx.Provide(config.CurrentEnvironment)
+}
+
+func buildFx(levels [][]*Module, watcher *configWatcher, level int) fx.Option {
+	opts := make([]fx.Option, 0)
+	// fx invokes child modules in the order they are added, so the deeper level goes first
+	// to invoke dependencies before the modules that depend on them
+	if level+1 < len(levels) {
+		opts = append(opts, buildFx(levels, watcher, level+1))
 	}
 
-	if len(deps) > 0 {
-		opts = append(opts, buildFx(deps, builtModules, level+1))
+	if level < len(levels) {
+		for _, module := range levels[level] {
+			opts = append(opts, module.buildFx(watcher))
+		}
 	}
 
 	return fx.Module(fmt.Sprintf("system-container-level-%d", level), opts...)
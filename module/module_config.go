@@ -2,12 +2,16 @@ package module
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"sort"
 	"strings"
 
+	"braces.dev/errtrace"
 	"github.com/fatih/structs"
+	"github.com/go-modulus/modulus/config"
 	"github.com/sethvargo/go-envconfig"
 )
 
@@ -15,6 +19,20 @@ type ConfigEnvVariable struct {
 	Key     string `json:"key"`
 	Value   string `json:"value"`
 	Comment string `json:"comment"`
+	// Secret variables have the `secret:"true"` tag in the config. Their values are always empty.
+	Secret bool `json:"secret,omitempty"`
+}
+
+// configVariable is a variable read from the config struct.
+type configVariable struct {
+	ConfigEnvVariable
+	// defaultValue is the value of the "default" option of the env tag. It is empty for secrets.
+	defaultValue string
+	// isSet is true if the value of the config field is not empty. It is the only thing exposed about secrets.
+	isSet bool
+	// inCode is true if the field is set in the config struct passed to InitConfig.
+	// Env variables don't change such values.
+	inCode bool
 }
 
 func (v *ConfigEnvVariable) SetComment(comment string) {
@@ -25,7 +43,7 @@ func GetEnvVariablesFromConfig[T any](config T) []ConfigEnvVariable {
 	vars := getVariables[T](config, true)
 	var envVars []ConfigEnvVariable
 	for _, value := range vars {
-		envVars = append(envVars, value)
+		envVars = append(envVars, value.ConfigEnvVariable)
 	}
 	sort.Slice(
 		envVars, func(i, j int) bool {
@@ -39,44 +57,75 @@ func WriteEnvVariablesToFile(
 	envVars []ConfigEnvVariable,
 	filePath string,
 ) error {
-	envVarsMap := make(map[string]ConfigEnvVariable)
-	for _, envVar := range envVars {
-		envVarsMap[envVar.Key] = envVar
-	}
 	envFileContent, err := os.ReadFile(filePath)
 	if err != nil {
 		return err
 	}
-	lines := strings.Split(string(envFileContent), "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "#") {
+	return os.WriteFile(filePath, appendEnvVariables(envFileContent, envVars), 0644)
+}
+
+// appendEnvVariables adds the variables that are not defined in the .env file content to the end of it.
+// The rest of the content is kept unchanged.
+func appendEnvVariables(envFileContent []byte, envVars []ConfigEnvVariable) []byte {
+	envFile := config.ParseEnv(envFileContent)
+	added := false
+	for _, envVar := range envVars {
+		if envFile.Has(envVar.Key) {
 			continue
 		}
-		parts := strings.Split(line, "=")
-		if len(parts) < 2 {
-			continue
+		if !added {
+			envFile.AppendBlankLine()
+			added = true
 		}
-		key := strings.TrimSpace(parts[0])
-		delete(envVarsMap, key)
+		envFile.Append(envVar.Key, envVar.Value, envVar.Comment)
 	}
-	if len(envVarsMap) > 0 {
-		lines = append(lines, "")
+	return envFile.Bytes()
+}
 
-		for _, v := range envVars {
-			if envVar, ok := envVarsMap[v.Key]; ok {
-				if envVar.Comment != "" {
-					lines = append(lines, fmt.Sprintf("# %s", envVar.Comment))
-				}
-				lines = append(lines, fmt.Sprintf("%s=%s", envVar.Key, envVar.Value))
-			}
+// secretKeyParts are the parts of variable names that are treated as secrets in the exported graph
+// even if the config field has no `secret:"true"` tag.
+var secretKeyParts = []string{"SECRET", "PASSWORD", "PASSWD", "TOKEN", "PRIVATE_KEY", "API_KEY", "CREDENTIALS"}
+
+// isSecretEnvKey returns true if the variable name looks like the name of a secret.
+func isSecretEnvKey(key string) bool {
+	key = strings.ToUpper(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
 		}
-		lines = append(lines, "")
 	}
-	return os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0644)
+	return false
 }
 
-func getVariables[T any](config T, initDefaults bool) map[string]ConfigEnvVariable {
-	envVariables := make(map[string]ConfigEnvVariable)
+// newSecretLookuper returns the lookuper of env variables that resolves the references in the values of secrets.
+// The env variables are looked up in env first and then in the process environment.
+func newSecretLookuper(ctx context.Context, vars map[string]configVariable, env map[string]string) (
+	envconfig.Lookuper,
+	error,
+) {
+	lookuper := envconfig.MultiLookuper(envconfig.MapLookuper(env), envconfig.OsLookuper())
+	resolved := make(map[string]string)
+	var errs []error
+	for key, v := range vars {
+		if !v.Secret {
+			continue
+		}
+		value, ok := lookuper.Lookup(key)
+		if !ok {
+			continue
+		}
+		secret, err := config.ResolveSecret(ctx, value)
+		if err != nil {
+			errs = append(errs, errtrace.Errorf("%s: %w", key, err))
+			continue
+		}
+		resolved[key] = secret
+	}
+	return envconfig.MultiLookuper(envconfig.MapLookuper(resolved), lookuper), errors.Join(errs...)
+}
+
+func getVariables[T any](config T, initDefaults bool) map[string]configVariable {
+	envVariables := make(map[string]configVariable)
 	if !structs.IsStruct(config) {
 		return envVariables
 	}
@@ -93,7 +142,9 @@ func getVariables[T any](config T, initDefaults bool) map[string]ConfigEnvVariab
 		fieldValue := field.Value()
 		tagParts := strings.Split(tag, ",")
 		prefix := ""
+		defaultValue := ""
 		comment := field.Tag("comment")
+		secret := field.Tag("secret") == "true"
 		fieldName := strings.TrimSpace(tagParts[0])
 		if len(tagParts) > 1 {
 			for _, part := range tagParts {
@@ -108,23 +159,33 @@ func getVariables[T any](config T, initDefaults bool) map[string]ConfigEnvVariab
 				if optKey == "comment" {
 					comment = strings.TrimSpace(optParts[1])
 				}
+				if optKey == "default" {
+					defaultValue = strings.TrimSpace(strings.SplitN(part, "=", 2)[1])
+				}
 			}
 		}
 		if structs.IsStruct(fieldValue) {
 			subFields := getVariables(fieldValue, false)
 			for subFieldName, subFieldValue := range subFields {
-				envVariables[prefix+subFieldName] = ConfigEnvVariable{
-					Key:     prefix + subFieldName,
-					Value:   subFieldValue.Value,
-					Comment: subFieldValue.Comment,
-				}
+				subFieldValue.Key = prefix + subFieldName
+				envVariables[prefix+subFieldName] = subFieldValue
 			}
 		} else {
-			envVariables[fieldName] = ConfigEnvVariable{
-				Key:     fieldName,
-				Value:   fmt.Sprint(fieldValue),
-				Comment: comment,
+			v := configVariable{
+				ConfigEnvVariable: ConfigEnvVariable{
+					Key:     fieldName,
+					Value:   fmt.Sprint(fieldValue),
+					Comment: comment,
+					Secret:  secret,
+				},
+				defaultValue: defaultValue,
+				isSet:        fieldValue != nil && !reflect.ValueOf(fieldValue).IsZero(),
+			}
+			if secret {
+				v.Value = ""
+				v.defaultValue = ""
 			}
+			envVariables[fieldName] = v
 		}
 	}
 
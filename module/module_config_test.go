@@ -1,9 +1,13 @@
 package module_test
 
 import (
+	"os"
+	"path/filepath"
+	"testing"
+
 	"github.com/go-modulus/modulus/module"
 	"github.com/stretchr/testify/assert"
-	"testing"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetEnvVariablesFromConfig(t *testing.T) {
@@ -36,3 +40,27 @@ func TestGetEnvVariablesFromConfig(t *testing.T) {
 		},
 	)
 }
+
+func TestWriteEnvVariablesToFile(t *testing.T) {
+	t.Run(
+		"keep existing lines and append missing variables", func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			content := "# Hash in value\nA=a#b\nexport B='quoted' # comment\n"
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+			err := module.WriteEnvVariablesToFile(
+				[]module.ConfigEnvVariable{
+					{Key: "A", Value: "a"},
+					{Key: "B", Value: "b"},
+					{Key: "C", Value: "c d", Comment: "This is a comment"},
+				},
+				path,
+			)
+
+			require.NoError(t, err)
+			result, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, content+"\n# This is a comment\nC=\"c d\"\n", string(result))
+		},
+	)
+}
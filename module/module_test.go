@@ -4,7 +4,7 @@ import (
 	"context"
 	"testing"
 
-	"github.com/go-modulus/modulus/test"
+	"github.com/go-modulus/modulus/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/fx"
@@ -164,7 +164,8 @@ func TestModule_WithOptions(t *testing.T) {
 				SetOverriddenProvider("InterfaceA", NewA)
 
 			var intA InterfaceA
-			err := test.Invoke(
+			err := invoke(
+				t,
 				BuildFx(mod),
 				fx.Populate(&intA),
 			)
@@ -182,7 +183,8 @@ func TestModule_WithOptions(t *testing.T) {
 				WithOptions(OverrideAInterfaceOption[*OverrideA])
 
 			var intA InterfaceA
-			err := test.Invoke(
+			err := invoke(
+				t,
 				BuildFx(mod),
 				fx.Populate(&intA),
 			)
@@ -201,7 +203,8 @@ func TestModule_WithOptions(t *testing.T) {
 				WithOptions(OverrideAOption[*OverrideA])
 
 			var intA *A
-			err := test.Invoke(
+			err := invoke(
+				t,
 				BuildFx(mod),
 				fx.Populate(&intA),
 			)
@@ -211,6 +214,76 @@ func TestModule_WithOptions(t *testing.T) {
 	)
 }
 
+func TestWithOptionsFor(t *testing.T) {
+	t.Setenv("APP_ENV", "staging")
+	mod := NewModule("test").
+		SetOverriddenProvider("InterfaceA", NewA).
+		AddProviders(NewOverrideA).
+		WithOptions(
+			WithOptionsFor(config.EnvProd, OverrideAInterfaceOption[*OverrideA]),
+		)
+
+	var intA InterfaceA
+	var env config.Environment
+	err := invoke(
+		t,
+		BuildFx(mod),
+		ProvideEnvironment(),
+		fx.Populate(&intA, &env),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "A", intA.MethodA())
+	assert.Equal(t, config.EnvStaging, env)
+
+	mod = NewModule("test").
+		SetOverriddenProvider("InterfaceA", NewA).
+		AddProviders(NewOverrideA).
+		WithOptions(
+			WithOptionsFor(config.EnvStaging, OverrideAInterfaceOption[*OverrideA]),
+		)
+	err = invoke(
+		t,
+		BuildFx(mod),
+		fx.Populate(&intA),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "OverrideA", intA.MethodA())
+}
+
+// invoke starts the app. The test package cannot be used here because it depends on the module package.
+func invoke(t *testing.T, options ...fx.Option) error {
+	app := fx.New(append([]fx.Option{fx.NopLogger}, options...)...)
+	t.Cleanup(
+		func() {
+			_ = app.Stop(context.Background())
+		},
+	)
+	return app.Start(context.Background())
+}
+
+func TestBuildFx_SeveralCalls(t *testing.T) {
+	t.Parallel()
+	var params struct {
+		fx.In
+
+		Graphs []*Graph `group:"module.graphs"`
+		Env    config.Environment
+	}
+	err := invoke(
+		t,
+		BuildFx(NewModule("first").AddProviders(NewA)),
+		BuildFx(NewModule("second").AddProviders(NewAObj)),
+		ProvideEnvironment(),
+		fx.Populate(&params),
+	)
+
+	t.Log("When two BuildFx options are used in one app")
+	t.Log("	Then the app starts with the graphs of both and one environment")
+	require.NoError(t, err)
+	assert.Len(t, params.Graphs, 2)
+	assert.Equal(t, config.CurrentEnvironment(), params.Env)
+}
+
 type InterfaceA interface {
 	MethodA() string
 }
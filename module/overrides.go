@@ -0,0 +1,105 @@
+package module
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/go-modulus/modulus/errors/errsys"
+	"go.uber.org/fx"
+)
+
+var ErrUnusedConfigOverride = errsys.New(
+	"unused config override",
+	"The overridden config is not initialized by any module. Check the type of the config",
+)
+
+// Overrides change the configs of the modules for one BuildFxWithOverrides call.
+// The modules and the process environment are not changed,
+// so apps with different overrides can be built from the same modules in parallel tests.
+type Overrides struct {
+	// Env has priority over the process environment when the configs are read.
+	Env map[string]string
+	// Configs replace the config structs of the same type passed to InitConfig.
+	// Like in InitConfig, the fields with zero values are filled with the env variables.
+	Configs []any
+}
+
+func (o Overrides) isEmpty() bool {
+	return len(o.Env) == 0 && len(o.Configs) == 0
+}
+
+// BuildFxWithOverrides works like BuildFx, but reads the configs of the modules with the overrides.
+func BuildFxWithOverrides(overrides Overrides, modules ...*Module) fx.Option {
+	if overrides.isEmpty() {
+		return BuildFx(modules...)
+	}
+	modules, err := overrides.apply(modules)
+	if err != nil {
+		return fx.Error(err)
+	}
+	return BuildFx(modules...)
+}
+
+// apply returns the copies of the modules and their dependencies with the configs read again with the overrides.
+func (o Overrides) apply(modules []*Module) ([]*Module, error) {
+	configs := make(map[string]any, len(o.Configs))
+	for _, config := range o.Configs {
+		val := reflect.ValueOf(config)
+		if val.Kind() == reflect.Ptr {
+			config = val.Elem().Interface()
+		}
+		configs[(&Module{}).getConfigName(config)] = config
+	}
+	used := make(map[string]struct{}, len(configs))
+	copies := make(map[*Module]*Module)
+	var copyModule func(m *Module) *Module
+	copyModule = func(m *Module) *Module {
+		if c, ok := copies[m]; ok {
+			return c
+		}
+		c := *m
+		copies[m] = &c
+		c.dependencies = make([]*Module, 0, len(m.dependencies))
+		for _, dep := range m.dependencies {
+			c.dependencies = append(c.dependencies, copyModule(dep))
+		}
+		c.env = o.Env
+		c.configs = make(map[string]interface{}, len(m.configs))
+		c.configErrors = make(map[string]error, len(m.configErrors))
+		c.configInitials = make(map[string]interface{}, len(m.configInitials))
+		c.envDetails = make(map[string]configVariable, len(m.envDetails))
+		c.envVars = nil
+		names := make([]string, 0, len(m.configInitials))
+		for name := range m.configInitials {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			initial := m.configInitials[name]
+			if config, ok := configs[name]; ok {
+				initial = config
+				used[name] = struct{}{}
+			}
+			c.InitConfig(initial)
+		}
+		return &c
+	}
+	result := make([]*Module, 0, len(modules))
+	for _, m := range modules {
+		result = append(result, copyModule(m))
+	}
+
+	var unused []string
+	for name := range configs {
+		if _, ok := used[name]; !ok {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return nil, errtrace.Errorf("%s: %w", strings.Join(unused, ", "), ErrUnusedConfigOverride)
+	}
+	return result, nil
+}
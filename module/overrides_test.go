@@ -0,0 +1,109 @@
+package module
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-modulus/modulus/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type overridesTestConfig struct {
+	Host string `env:"OVERRIDES_TEST_HOST, default=localhost"`
+	Port int    `env:"OVERRIDES_TEST_PORT, default=80"`
+}
+
+type overridesTestReloadableConfig struct {
+	Level string `env:"OVERRIDES_TEST_LEVEL, default=info"`
+}
+
+func TestBuildFxWithOverrides(t *testing.T) {
+	t.Parallel()
+	newModules := func() (*Module, *Module) {
+		dep := NewModule("overrides-dep").
+			InitConfig(overridesTestConfig{}).
+			WithOptions(InitReloadableConfig(overridesTestReloadableConfig{}))
+		return NewModule("overrides-root").AddDependencies(dep), dep
+	}
+
+	t.Run(
+		"read the configs with the env overrides", func(t *testing.T) {
+			t.Parallel()
+			root, dep := newModules()
+			var config overridesTestConfig
+			var reloadable *Reloadable[overridesTestReloadableConfig]
+
+			err := invoke(
+				t,
+				BuildFxWithOverrides(
+					Overrides{Env: map[string]string{"OVERRIDES_TEST_PORT": "8080", "OVERRIDES_TEST_LEVEL": "debug"}},
+					root,
+				),
+				fx.Populate(&config, &reloadable),
+			)
+
+			t.Log("When the modules are built with the env overrides")
+			t.Log("	Then the configs of this app read them and the modules keep their own configs")
+			require.NoError(t, err)
+			assert.Equal(t, overridesTestConfig{Host: "localhost", Port: 8080}, config)
+			require.NoError(t, reloadable.Reload(context.Background()))
+			assert.Equal(t, "debug", reloadable.Get().Level)
+			assert.Equal(t, overridesTestConfig{Host: "localhost", Port: 80}, dep.configs[dep.getConfigName(config)])
+		},
+	)
+
+	t.Run(
+		"replace the config struct", func(t *testing.T) {
+			t.Parallel()
+			root, _ := newModules()
+			var config overridesTestConfig
+
+			err := invoke(
+				t,
+				BuildFxWithOverrides(
+					Overrides{
+						Env:     map[string]string{"OVERRIDES_TEST_PORT": "8080"},
+						Configs: []any{overridesTestConfig{Host: "example.com"}},
+					},
+					root,
+				),
+				fx.Populate(&config),
+			)
+
+			t.Log("When a config struct is overridden")
+			t.Log("	Then it replaces the struct passed to InitConfig and its zero fields are read from the env overrides")
+			require.NoError(t, err)
+			assert.Equal(t, overridesTestConfig{Host: "example.com", Port: 8080}, config)
+		},
+	)
+
+	t.Run(
+		"fail on the config that is not used by modules", func(t *testing.T) {
+			t.Parallel()
+			root, _ := newModules()
+			type UnknownConfig struct{}
+
+			err := invoke(t, BuildFxWithOverrides(Overrides{Configs: []any{&UnknownConfig{}}}, root))
+
+			t.Log("When a config that is not used by the modules is overridden")
+			t.Log("	Then the app fails")
+			require.ErrorIs(t, err, ErrUnusedConfigOverride)
+		},
+	)
+
+	t.Run(
+		"validate the overridden configs", func(t *testing.T) {
+			t.Parallel()
+			root, _ := newModules()
+
+			err := invoke(t, BuildFxWithOverrides(Overrides{Env: map[string]string{"OVERRIDES_TEST_PORT": "port"}}, root))
+
+			t.Log("When an overridden env variable is invalid")
+			t.Log("	Then the app fails with the validation error of the config")
+			require.Error(t, err)
+			assert.Contains(t, errors.Meta(err), "github.com/go-modulus/modulus/module.overridesTestConfig")
+		},
+	)
+}
@@ -0,0 +1,272 @@
+package module
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-modulus/modulus/config"
+	"github.com/go-modulus/modulus/errors"
+	"github.com/go-modulus/modulus/errors/erruser"
+	"go.uber.org/fx"
+)
+
+// ConfigWatchInterval is how often the .env and config files are checked for changes when WatchConfig is used.
+const ConfigWatchInterval = 2 * time.Second
+
+// Reloadable holds the config value that can be changed while the application is running.
+// It is provided by the module for the configs added with InitReloadableConfig.
+// The config is read from the env variables again when Reload is called.
+// With the WatchConfig option of the app it is also reloaded on SIGHUP or when the .env and config files loaded by
+// config.LoadDefaultEnv or config.Loader are changed. The new value is validated like in BuildFx, an invalid value is ignored.
+type Reloadable[T any] struct {
+	name    string
+	initial T
+	env     map[string]string
+
+	mu          sync.RWMutex
+	value       T
+	subscribers map[int]func(T)
+	nextID      int
+}
+
+func newReloadable[T any](name string, initial, value T, env map[string]string) *Reloadable[T] {
+	return &Reloadable[T]{
+		name:        name,
+		initial:     initial,
+		env:         env,
+		value:       value,
+		subscribers: make(map[int]func(T)),
+	}
+}
+
+// InitReloadableConfig inits the config like Module.InitConfig and makes it reloadable.
+// The module provides both T with the value at the start and *Reloadable[T] with the current value.
+// T must be a struct, not a pointer to it.
+func InitReloadableConfig[T any](cfg T) Option {
+	return func(m *Module) *Module {
+		m = m.InitConfig(cfg)
+		name := m.getConfigName(cfg)
+		m.reloadables[name] = func(initial, value any, env map[string]string) reloader {
+			return newReloadable[T](name, initial.(T), value.(T), env)
+		}
+		return m
+	}
+}
+
+// Get returns the current value of the config.
+func (r *Reloadable[T]) Get() T {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.value
+}
+
+// Subscribe calls fn with the new value of the config after each change.
+// The returned function cancels the subscription.
+func (r *Reloadable[T]) Subscribe(fn func(T)) (unsubscribe func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.nextID
+	r.nextID++
+	r.subscribers[id] = fn
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.subscribers, id)
+	}
+}
+
+// Reload reads the config from the env variables again and validates it.
+// Subscribers are notified only if the value is changed. If the new value is invalid, the current one is kept.
+func (r *Reloadable[T]) Reload(ctx context.Context) error {
+	ptr := reflect.New(reflect.TypeOf(r.initial))
+	ptr.Elem().Set(reflect.ValueOf(r.initial))
+	processErr := processConfig(ctx, ptr.Interface(), r.env)
+	value := ptr.Elem().Interface().(T)
+	if errs := validateConfig(ctx, r.name, value, processErr); len(errs) > 0 {
+		return erruser.NewValidationError(errs...)
+	}
+
+	r.mu.Lock()
+	if reflect.DeepEqual(r.value, value) {
+		r.mu.Unlock()
+		return nil
+	}
+	r.value = value
+	subscribers := make([]func(T), 0, len(r.subscribers))
+	for id := 0; id < r.nextID; id++ {
+		if fn, ok := r.subscribers[id]; ok {
+			subscribers = append(subscribers, fn)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(value)
+	}
+	return nil
+}
+
+func (r *Reloadable[T]) configName() string {
+	return r.name
+}
+
+type reloader interface {
+	Reload(ctx context.Context) error
+	configName() string
+}
+
+// watchConfig is supplied by WatchConfig to start the config watchers of all BuildFx calls.
+type watchConfig struct{}
+
+// WatchConfig makes the app reload the reloadable configs on SIGHUP and when the .env and config files are changed.
+// Without it the configs are reloaded only by the Reload calls. Add it once per app:
+//
+//	fx.New(module.BuildFx(modules...), module.WatchConfig())
+//
+// The watcher handles SIGHUP, so it no longer terminates the process,
+// and checks the files every ConfigWatchInterval.
+func WatchConfig() fx.Option {
+	return fx.Supply(&watchConfig{})
+}
+
+type configWatcherParams struct {
+	fx.In
+
+	Watch  *watchConfig `optional:"true"`
+	Logger *slog.Logger `optional:"true"`
+}
+
+// configWatcher reloads the reloadable configs of the module tree on SIGHUP and when the .env files are changed.
+// It is started only if the app has the WatchConfig option.
+type configWatcher struct {
+	reloaders map[*Module][]reloader
+	logger    *slog.Logger
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func newConfigWatcher(tree *moduleTree) *configWatcher {
+	w := &configWatcher{
+		reloaders: make(map[*Module][]reloader),
+	}
+	for _, node := range tree.nodes {
+		m := node.module
+		for name, factory := range m.reloadables {
+			w.reloaders[m] = append(w.reloaders[m], factory(m.configInitials[name], m.configs[name], m.env))
+		}
+	}
+	return w
+}
+
+func (w *configWatcher) isEmpty() bool {
+	return len(w.reloaders) == 0
+}
+
+// supplies returns the *Reloadable values of the module.
+func (w *configWatcher) supplies(m *Module) []interface{} {
+	supplies := make([]interface{}, 0, len(w.reloaders[m]))
+	for _, r := range w.reloaders[m] {
+		supplies = append(supplies, r)
+	}
+	return supplies
+}
+
+func (w *configWatcher) invoke(lc fx.Lifecycle, params configWatcherParams) {
+	if params.Watch == nil {
+		return
+	}
+	w.logger = params.Logger
+	if w.logger == nil {
+		w.logger = slog.Default()
+	}
+	w.logger = w.logger.With(slog.String("component", "module"))
+	lc.Append(
+		fx.Hook{
+			OnStart: func(context.Context) error {
+				w.start()
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				close(w.stop)
+				<-w.done
+				return nil
+			},
+		},
+	)
+}
+
+func (w *configWatcher) start() {
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	ticker := time.NewTicker(ConfigWatchInterval)
+	files := envFilesState()
+
+	go func() {
+		defer close(w.done)
+		defer ticker.Stop()
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-signals:
+				w.logger.Info("reloading configs on SIGHUP")
+				files = envFilesState()
+				w.reload(context.Background())
+			case <-ticker.C:
+				state := envFilesState()
+				if reflect.DeepEqual(state, files) {
+					continue
+				}
+				files = state
+				w.logger.Info("reloading configs on .env change")
+				w.reload(context.Background())
+			}
+		}
+	}()
+}
+
+// reload reads the .env files again and reloads all configs. Errors are logged, invalid configs keep their values.
+func (w *configWatcher) reload(ctx context.Context) {
+	if err := config.ReloadDefaultEnv(); err != nil {
+		w.logger.Error("cannot reload .env files", slog.String("error", err.Error()))
+		return
+	}
+	for _, reloaders := range w.reloaders {
+		for _, r := range reloaders {
+			if err := r.Reload(ctx); err != nil {
+				w.logger.Error(
+					"cannot reload config",
+					slog.String("config", r.configName()),
+					slog.String("error", err.Error()),
+					slog.Any("details", errors.Meta(err)),
+				)
+			}
+		}
+	}
+}
+
+type envFileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func envFilesState() map[string]envFileState {
+	state := make(map[string]envFileState)
+	for _, path := range config.LoadedFiles() {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		state[path] = envFileState{modTime: info.ModTime(), size: info.Size()}
+	}
+	return state
+}
@@ -0,0 +1,109 @@
+package module_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-modulus/modulus/module"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type reloadConfig struct {
+	Level string `env:"RELOAD_TEST_LEVEL, default=info"`
+}
+
+func (c reloadConfig) Validate(context.Context) error {
+	if c.Level == "invalid" {
+		return errors.New("invalid level")
+	}
+	return nil
+}
+
+func TestReloadable(t *testing.T) {
+	t.Setenv("RELOAD_TEST_LEVEL", "debug")
+	m := module.NewModule("m").WithOptions(module.InitReloadableConfig(reloadConfig{}))
+
+	var initial reloadConfig
+	var reloadable *module.Reloadable[reloadConfig]
+	app := fx.New(module.BuildFx(m), module.WatchConfig(), fx.NopLogger, fx.Populate(&initial, &reloadable))
+	require.NoError(t, app.Err())
+	assert.Equal(t, "debug", initial.Level)
+	assert.Equal(t, "debug", reloadable.Get().Level)
+
+	var notified []string
+	unsubscribe := reloadable.Subscribe(
+		func(c reloadConfig) {
+			notified = append(notified, c.Level)
+		},
+	)
+
+	t.Run(
+		"apply the new value", func(t *testing.T) {
+			t.Setenv("RELOAD_TEST_LEVEL", "warn")
+
+			require.NoError(t, reloadable.Reload(context.Background()))
+
+			assert.Equal(t, "warn", reloadable.Get().Level)
+			assert.Equal(t, []string{"warn"}, notified)
+		},
+	)
+
+	t.Run(
+		"do not notify about the same value", func(t *testing.T) {
+			t.Setenv("RELOAD_TEST_LEVEL", "warn")
+
+			require.NoError(t, reloadable.Reload(context.Background()))
+
+			assert.Equal(t, []string{"warn"}, notified)
+		},
+	)
+
+	t.Run(
+		"keep the value if the new one is invalid", func(t *testing.T) {
+			t.Setenv("RELOAD_TEST_LEVEL", "invalid")
+
+			err := reloadable.Reload(context.Background())
+
+			require.Error(t, err)
+			assert.Equal(t, "warn", reloadable.Get().Level)
+			assert.Equal(t, []string{"warn"}, notified)
+		},
+	)
+
+	t.Run(
+		"unsubscribe", func(t *testing.T) {
+			unsubscribe()
+			t.Setenv("RELOAD_TEST_LEVEL", "error")
+
+			require.NoError(t, reloadable.Reload(context.Background()))
+
+			assert.Equal(t, "error", reloadable.Get().Level)
+			assert.Equal(t, []string{"warn"}, notified)
+			assert.Equal(t, "debug", initial.Level)
+		},
+	)
+
+	t.Run(
+		"reload on SIGHUP", func(t *testing.T) {
+			require.NoError(t, app.Start(context.Background()))
+			defer func() {
+				require.NoError(t, app.Stop(context.Background()))
+			}()
+			t.Setenv("RELOAD_TEST_LEVEL", "sighup")
+
+			require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+			assert.Eventually(
+				t, func() bool {
+					return reloadable.Get().Level == "sighup"
+				}, time.Second, 10*time.Millisecond,
+			)
+		},
+	)
+}
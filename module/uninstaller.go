@@ -0,0 +1,191 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/go-modulus/modulus/config"
+	"github.com/go-modulus/modulus/errors/errsys"
+)
+
+var ErrModuleIsRequired = errsys.New(
+	"module is required",
+	"Module cannot be uninstalled because other installed modules depend on it",
+)
+
+// Uninstall reverts the changes made by Install for the module, but not for its dependencies.
+// installed is the list of manifestos of all modules installed in the project.
+// The uninstallation is refused if any other installed module depends on the module.
+// Env variables of the module are removed from the .env files only if their values are not changed,
+// other lines and comments are kept. Installed files are deleted only if their content has the hash
+// recorded in InstallStateFile by Install, so the module sources are not fetched again.
+// Post-install commands are not reverted.
+func (i *Installer) Uninstall(
+	_ context.Context,
+	projectRoot string,
+	manifesto Manifesto,
+	installed []Manifesto,
+) (*InstallReport, error) {
+	return i.uninstall(projectRoot, manifesto, installed, false)
+}
+
+// DryRunUninstall reports the changes Uninstall would make without touching the project.
+func (i *Installer) DryRunUninstall(
+	_ context.Context,
+	projectRoot string,
+	manifesto Manifesto,
+	installed []Manifesto,
+) (*InstallReport, error) {
+	return i.uninstall(projectRoot, manifesto, installed, true)
+}
+
+func (i *Installer) uninstall(
+	projectRoot string,
+	manifesto Manifesto,
+	installed []Manifesto,
+	dryRun bool,
+) (*InstallReport, error) {
+	var dependants []string
+	for _, m := range installed {
+		if m.Name == manifesto.Name {
+			continue
+		}
+		for _, dep := range m.Install.Dependencies {
+			if dep == manifesto.Name {
+				dependants = append(dependants, m.Name)
+			}
+		}
+	}
+	if len(dependants) > 0 {
+		return nil, errtrace.Errorf(
+			"%s is required by %s: %w",
+			manifesto.Name,
+			strings.Join(dependants, ", "),
+			ErrModuleIsRequired,
+		)
+	}
+
+	report := &InstallReport{
+		Modules: []string{manifesto.Name},
+		DryRun:  dryRun,
+	}
+	if len(manifesto.Install.EnvVars) > 0 {
+		changes, err := i.uninstallEnvVars(projectRoot, manifesto.Install.EnvVars, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		report.Changes = append(report.Changes, changes...)
+	}
+	state, err := readInstallState(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range manifesto.Install.Files {
+		change, err := i.uninstallFile(projectRoot, file, state, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			report.Changes = append(report.Changes, *change)
+		}
+		delete(state.Files, file.DestFile)
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	return report, state.write(projectRoot)
+}
+
+// uninstallEnvVars removes the variables from .env and .env.* files in the project root.
+func (i *Installer) uninstallEnvVars(projectRoot string, envVars []ConfigEnvVariable, dryRun bool) ([]FileChange, error) {
+	envFiles, err := filepath.Glob(filepath.Join(projectRoot, ".env*"))
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	sort.Strings(envFiles)
+
+	var changes []FileChange
+	for _, envFile := range envFiles {
+		name := filepath.Base(envFile)
+		if name != ".env" && !strings.HasPrefix(name, ".env.") {
+			continue
+		}
+		old, err := os.ReadFile(envFile)
+		if err != nil {
+			return nil, errtrace.Wrap(err)
+		}
+		newContent := removeEnvVariables(old, envVars)
+		if bytes.Equal(old, newContent) {
+			continue
+		}
+		changes = append(changes, FileChange{Path: name, Old: old, New: newContent})
+		if dryRun {
+			continue
+		}
+		if err := os.WriteFile(envFile, newContent, 0644); err != nil {
+			return nil, errtrace.Wrap(err)
+		}
+	}
+	return changes, nil
+}
+
+func (i *Installer) uninstallFile(
+	projectRoot string,
+	file InstalledFile,
+	state *installState,
+	dryRun bool,
+) (*FileChange, error) {
+	if !filepath.IsLocal(file.DestFile) {
+		return nil, errtrace.Errorf("%s: %w", file.DestFile, ErrInvalidDestFile)
+	}
+	dest := filepath.Join(projectRoot, file.DestFile)
+	old, err := os.ReadFile(dest)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, errtrace.Wrap(err)
+	}
+	installed, ok := state.Files[file.DestFile]
+	if !ok {
+		return &FileChange{
+			Path:    file.DestFile,
+			Old:     old,
+			New:     old,
+			Skipped: true,
+			Reason:  "was not created by the installer",
+		}, nil
+	}
+	if contentHash(old) != installed.SHA256 {
+		return &FileChange{
+			Path:    file.DestFile,
+			Old:     old,
+			New:     old,
+			Skipped: true,
+			Reason:  "was changed after installation",
+		}, nil
+	}
+	change := &FileChange{Path: file.DestFile, Old: old}
+	if dryRun {
+		return change, nil
+	}
+	return change, errtrace.Wrap(os.Remove(dest))
+}
+
+// removeEnvVariables removes the definitions of the variables with unchanged values from the .env file content.
+// Every definition is checked separately, the ones with changed values are kept.
+// A comment line right above a removed variable is removed too if it is the comment of the variable.
+func removeEnvVariables(envFileContent []byte, envVars []ConfigEnvVariable) []byte {
+	envFile := config.ParseEnv(envFileContent)
+	for _, envVar := range envVars {
+		envFile.DeleteValueWithComment(envVar.Key, envVar.Value, envVar.Comment)
+	}
+	return envFile.Bytes()
+}
@@ -0,0 +1,172 @@
+package module_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-modulus/modulus/module"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstaller_Uninstall(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"revert installed module", func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			envContent := "# App settings\nAPP_NAME=test\n"
+			require.NoError(t, os.WriteFile(filepath.Join(root, ".env"), []byte(envContent), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(root, ".env.prod"), []byte("LOGGER_LEVEL=error\n"), 0644))
+			registry := testManifestos()
+			installer, _ := newTestInstaller(registry)
+			_, err := installer.Install(context.Background(), root, registry[0])
+			require.NoError(t, err)
+
+			report, err := installer.Uninstall(context.Background(), root, registry[0], registry[:1])
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"logger"}, report.Modules)
+			env, err := os.ReadFile(filepath.Join(root, ".env"))
+			require.NoError(t, err)
+			assert.NotContains(t, string(env), "LOGGER_LEVEL")
+			assert.NotContains(t, string(env), "# Log level")
+			assert.True(t, strings.HasPrefix(string(env), envContent))
+			prodEnv, err := os.ReadFile(filepath.Join(root, ".env.prod"))
+			require.NoError(t, err)
+			assert.Equal(t, "LOGGER_LEVEL=error\n", string(prodEnv))
+			assert.NoFileExists(t, filepath.Join(root, "config/logger.yaml"))
+		},
+	)
+
+	t.Run(
+		"keep changed values and files", func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			registry := testManifestos()
+			installer, _ := newTestInstaller(registry)
+			_, err := installer.Install(context.Background(), root, registry[0])
+			require.NoError(t, err)
+			envFile := filepath.Join(root, ".env")
+			env, err := os.ReadFile(envFile)
+			require.NoError(t, err)
+			env = []byte(strings.Replace(string(env), "LOGGER_LEVEL=debug", "LOGGER_LEVEL=\"info\"", 1))
+			require.NoError(t, os.WriteFile(envFile, env, 0644))
+			configFile := filepath.Join(root, "config/logger.yaml")
+			require.NoError(t, os.WriteFile(configFile, []byte("level: info\n"), 0644))
+
+			report, err := installer.DryRunUninstall(context.Background(), root, registry[0], registry[:1])
+			require.NoError(t, err)
+			var diff strings.Builder
+			require.NoError(t, report.WriteDiff(&diff))
+			assert.Contains(t, diff.String(), "# config/logger.yaml was changed after installation, skipped\n")
+
+			_, err = installer.Uninstall(context.Background(), root, registry[0], registry[:1])
+
+			require.NoError(t, err)
+			newEnv, err := os.ReadFile(envFile)
+			require.NoError(t, err)
+			assert.Equal(t, string(env), string(newEnv))
+			assert.FileExists(t, configFile)
+		},
+	)
+
+	t.Run(
+		"compare files with the installed content", func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			registry := testManifestos()
+			installer, _ := newTestInstaller(registry)
+			_, err := installer.Install(context.Background(), root, registry[0])
+			require.NoError(t, err)
+			require.FileExists(t, filepath.Join(root, module.InstallStateFile))
+
+			t.Log("Given the module source is changed upstream and cannot be fetched")
+			offline := module.NewInstaller(module.NewFSFetcher(fstest.MapFS{}, testBaseUrl), registry, nil)
+
+			_, err = offline.Uninstall(context.Background(), root, registry[0], registry[:1])
+
+			t.Log("	Then the unchanged installed file is deleted")
+			require.NoError(t, err)
+			assert.NoFileExists(t, filepath.Join(root, "config/logger.yaml"))
+			assert.NoFileExists(t, filepath.Join(root, module.InstallStateFile))
+		},
+	)
+
+	t.Run(
+		"keep files not created by the installer", func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			configFile := filepath.Join(root, "config/logger.yaml")
+			require.NoError(t, os.MkdirAll(filepath.Dir(configFile), 0755))
+			require.NoError(t, os.WriteFile(configFile, []byte("level: debug\n"), 0644))
+			registry := testManifestos()
+			installer, _ := newTestInstaller(registry)
+			_, err := installer.Install(context.Background(), root, registry[0])
+			require.NoError(t, err)
+
+			report, err := installer.Uninstall(context.Background(), root, registry[0], registry[:1])
+
+			require.NoError(t, err)
+			var diff strings.Builder
+			require.NoError(t, report.WriteDiff(&diff))
+			assert.Contains(t, diff.String(), "# config/logger.yaml was not created by the installer, skipped\n")
+			assert.FileExists(t, configFile)
+		},
+	)
+
+	t.Run(
+		"remove only unchanged definitions", func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			envFile := filepath.Join(root, ".env")
+			require.NoError(t, os.WriteFile(envFile, []byte("LOGGER_LEVEL=debug\nLOGGER_LEVEL=info\n"), 0644))
+			registry := testManifestos()
+			installer, _ := newTestInstaller(registry)
+
+			_, err := installer.Uninstall(context.Background(), root, registry[0], registry[:1])
+
+			require.NoError(t, err)
+			env, err := os.ReadFile(envFile)
+			require.NoError(t, err)
+			assert.Equal(t, "LOGGER_LEVEL=info\n", string(env))
+		},
+	)
+
+	t.Run(
+		"dry run reports removed files", func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			registry := testManifestos()
+			installer, _ := newTestInstaller(registry)
+			_, err := installer.Install(context.Background(), root, registry[0])
+			require.NoError(t, err)
+
+			report, err := installer.DryRunUninstall(context.Background(), root, registry[0], registry[:1])
+
+			require.NoError(t, err)
+			var diff strings.Builder
+			require.NoError(t, report.WriteDiff(&diff))
+			assert.Contains(t, diff.String(), "--- a/.env\n+++ b/.env\n-# Log level\n-LOGGER_LEVEL=debug\n")
+			assert.Contains(t, diff.String(), "--- a/config/logger.yaml\n+++ /dev/null\n-level: debug\n")
+			assert.FileExists(t, filepath.Join(root, "config/logger.yaml"))
+		},
+	)
+
+	t.Run(
+		"refuse to uninstall a dependency", func(t *testing.T) {
+			t.Parallel()
+			registry := testManifestos()
+			installer, _ := newTestInstaller(registry)
+
+			_, err := installer.Uninstall(context.Background(), t.TempDir(), registry[0], registry)
+
+			require.ErrorIs(t, err, module.ErrModuleIsRequired)
+			assert.Contains(t, err.Error(), "logger is required by http")
+		},
+	)
+}
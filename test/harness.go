@@ -0,0 +1,146 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-modulus/modulus/module"
+	"go.uber.org/fx"
+)
+
+// Harness runs the modules in a test the same way as the application does.
+// The fx app is built with module.BuildFx, started by Start and stopped when the test finishes.
+// Env variables and configs can be overridden for the app of one test with Env and Config.
+type Harness struct {
+	t       testing.TB
+	modules []*module.Module
+	options []fx.Option
+	targets []interface{}
+	app     *fx.App
+	env     map[string]string
+	configs []interface{}
+}
+
+// NewHarness returns the harness of the modules. It loads the .env and .env.test files like LoadEnv.
+// Note: configs are read when the modules are created, so create them after LoadEnv is called
+// to fill the configs from the test env files.
+func NewHarness(t testing.TB, modules ...*module.Module) *Harness {
+	t.Helper()
+	loadEnv(projectRoot())
+	return &Harness{
+		t:       t,
+		modules: modules,
+	}
+}
+
+// Replace replaces the value of type T provided by any module with the given one.
+// T can be an interface, so a fake can be used without knowing the name passed to SetOverriddenProvider:
+//
+//	test.Replace[http.Router](h, fakeRouter)
+//
+// The value is set with fx.Decorate, so all dependants get it. The constructor of the replaced value is called
+// only if another value it returns is used.
+func Replace[T any](h *Harness, value T) *Harness {
+	h.t.Helper()
+	h.mustNotBeStarted()
+	h.options = append(
+		h.options, fx.Decorate(
+			func() T {
+				return value
+			},
+		),
+	)
+	return h
+}
+
+// Env sets the env variables for the configs of this app only.
+// The process environment is not changed, so parallel tests can use different values.
+// Note: the variables are read only by the module configs, not by os.Getenv.
+func (h *Harness) Env(env map[string]string) *Harness {
+	h.t.Helper()
+	h.mustNotBeStarted()
+	if h.env == nil {
+		h.env = make(map[string]string, len(env))
+	}
+	for key, value := range env {
+		h.env[key] = value
+	}
+	return h
+}
+
+// Config replaces the config structs passed to InitConfig by the modules with the values of the same type
+// for this app only, e.g. h.Config(http.ServeConfig{Address: "localhost:0"}).
+// Like in InitConfig, the fields with zero values are filled with the env variables.
+func (h *Harness) Config(configs ...interface{}) *Harness {
+	h.t.Helper()
+	h.mustNotBeStarted()
+	h.configs = append(h.configs, configs...)
+	return h
+}
+
+// Options adds fx options to the app, e.g. fx.Decorate or fx.Invoke.
+func (h *Harness) Options(options ...fx.Option) *Harness {
+	h.t.Helper()
+	h.mustNotBeStarted()
+	h.options = append(h.options, options...)
+	return h
+}
+
+// Populate fills the targets with the values from the container when the app is started.
+// Each target must be a pointer to the type provided by the modules.
+func (h *Harness) Populate(targets ...interface{}) *Harness {
+	h.t.Helper()
+	h.mustNotBeStarted()
+	h.targets = append(h.targets, targets...)
+	return h
+}
+
+// Start builds and starts the app. The test fails if it cannot be started.
+// The app is stopped by t.Cleanup.
+func (h *Harness) Start() *Harness {
+	h.t.Helper()
+	h.mustNotBeStarted()
+	options := append([]fx.Option{quietLogger(), h.build(), module.ProvideEnvironment()}, h.options...)
+	if len(h.targets) > 0 {
+		options = append(options, fx.Populate(h.targets...))
+	}
+	h.app = fx.New(options...)
+	if err := h.app.Start(context.Background()); err != nil {
+		h.t.Fatalf("cannot start the app: %v", err)
+	}
+	app := h.app
+	h.t.Cleanup(
+		func() {
+			if err := app.Stop(context.Background()); err != nil {
+				h.t.Errorf("cannot stop the app: %v", err)
+			}
+		},
+	)
+	return h
+}
+
+// Get starts the app and returns the value of type T from the container.
+// Use Populate and Start to get several values.
+func Get[T any](h *Harness) T {
+	h.t.Helper()
+	var value T
+	h.Populate(&value).Start()
+	return value
+}
+
+func (h *Harness) build() fx.Option {
+	return module.BuildFxWithOverrides(
+		module.Overrides{
+			Env:     h.env,
+			Configs: h.configs,
+		},
+		h.modules...,
+	)
+}
+
+func (h *Harness) mustNotBeStarted() {
+	h.t.Helper()
+	if h.app != nil {
+		h.t.Fatal("the harness is already started")
+	}
+}
@@ -0,0 +1,167 @@
+package test_test
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-modulus/modulus/http"
+	"github.com/go-modulus/modulus/module"
+	"github.com/go-modulus/modulus/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type englishGreeter struct{}
+
+func (englishGreeter) Greet() string {
+	return "hello"
+}
+
+type fakeGreeter struct{}
+
+func (fakeGreeter) Greet() string {
+	return "fake"
+}
+
+type service struct {
+	greeter greeter
+}
+
+func newService(g greeter) *service {
+	return &service{greeter: g}
+}
+
+func newGreeterModule() *module.Module {
+	return module.NewModule("greeter").
+		SetOverriddenProvider(
+			"greeter.Greeter", func() greeter {
+				return englishGreeter{}
+			},
+		).
+		AddProviders(newService)
+}
+
+func TestHarness(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"populate the values of the modules", func(t *testing.T) {
+			t.Parallel()
+			var s *service
+
+			test.NewHarness(t, newGreeterModule()).Populate(&s).Start()
+
+			t.Log("When the harness is started")
+			t.Log("	Then the values are populated from the modules in the test environment")
+			assert.Equal(t, "hello", s.greeter.Greet())
+			assert.Equal(t, "test", os.Getenv("APP_ENV"))
+		},
+	)
+
+	t.Run(
+		"replace the provider by type", func(t *testing.T) {
+			t.Parallel()
+			h := test.NewHarness(t, newGreeterModule())
+
+			s := test.Get[*service](test.Replace[greeter](h, fakeGreeter{}))
+
+			t.Log("When the value of an interface type is replaced")
+			t.Log("	Then the dependants get the fake")
+			assert.Equal(t, "fake", s.greeter.Greet())
+		},
+	)
+
+	t.Run(
+		"call the replaced constructor only for its other results", func(t *testing.T) {
+			t.Parallel()
+			var called atomic.Int32
+			newGreeters := func() (greeter, *englishGreeter) {
+				called.Add(1)
+				return englishGreeter{}, &englishGreeter{}
+			}
+			newModule := func() *module.Module {
+				return module.NewModule("greeters").AddProviders(newGreeters, newService)
+			}
+
+			s := test.Get[*service](test.Replace[greeter](test.NewHarness(t, newModule()), fakeGreeter{}))
+
+			t.Log("When only the replaced value is used")
+			t.Log("	Then the constructor is not called")
+			assert.Equal(t, "fake", s.greeter.Greet())
+			assert.Zero(t, called.Load())
+
+			h := test.Replace[greeter](test.NewHarness(t, newModule()), fakeGreeter{})
+			h.Populate(&s, new(*englishGreeter)).Start()
+
+			t.Log("When another result of the constructor is used")
+			t.Log("	Then the constructor is called, but the dependants still get the replaced value")
+			assert.Equal(t, "fake", s.greeter.Greet())
+			assert.Equal(t, int32(1), called.Load())
+		},
+	)
+
+	t.Run(
+		"override the configs of one app", func(t *testing.T) {
+			t.Parallel()
+			for _, address := range []string{"localhost:9001", "localhost:9002"} {
+				t.Run(
+					address, func(t *testing.T) {
+						t.Parallel()
+						var serveConfig http.ServeConfig
+
+						test.NewHarness(t, http.NewModule()).
+							Config(http.ServeConfig{Address: address}).
+							Env(map[string]string{"ROUTER_TTL": "1m"}).
+							Populate(&serveConfig).
+							Start()
+
+						t.Log("When parallel apps override the config and the env variables")
+						t.Log("	Then each app reads its own values and the process environment is not changed")
+						assert.Equal(t, address, serveConfig.Address)
+						assert.Equal(t, time.Minute, serveConfig.TTL)
+						assert.Empty(t, os.Getenv("ROUTER_TTL"))
+					},
+				)
+			}
+		},
+	)
+
+	t.Run(
+		"stop the app after the test", func(t *testing.T) {
+			t.Parallel()
+			stopped := false
+			t.Run(
+				"run the app", func(t *testing.T) {
+					test.NewHarness(t, newGreeterModule()).
+						Options(
+							fx.Invoke(
+								func(lc fx.Lifecycle) {
+									lc.Append(
+										fx.StopHook(
+											func(context.Context) {
+												stopped = true
+											},
+										),
+									)
+								},
+							),
+						).
+						Start()
+
+					require.False(t, stopped)
+				},
+			)
+
+			t.Log("When the test running the app finishes")
+			t.Log("	Then the app is stopped")
+			assert.True(t, stopped)
+		},
+	)
+}
@@ -22,7 +22,11 @@ func TestMain(m *testing.M, options ...fx.Option) {
 }
 
 func LoadEnv() {
-	envFileDir := projectRoot()
+	loadEnv(projectRoot())
+}
+
+// loadEnv loads the .env files of the project root once per test binary.
+func loadEnv(envFileDir string) {
 	initOnce.Do(
 		func() {
 			// force UTC timezone, otherwise it will use local timezone
@@ -37,18 +41,20 @@ func LoadEnv() {
 }
 
 func Invoke(options ...fx.Option) error {
-	opts := []fx.Option{
-		fx.WithLogger(
-			func() fxevent.Logger {
-				cfg := zap.NewDevelopmentConfig()
-				cfg.Level = zap.NewAtomicLevelAt(zapcore.ErrorLevel)
-				cfg.DisableCaller = true
-				logger, _ := cfg.Build()
-				return &fxevent.ZapLogger{Logger: logger}
-			},
-		),
-	}
-	app := fx.New(append(opts, options...)...)
+	app := fx.New(append([]fx.Option{quietLogger()}, options...)...)
 
 	return app.Start(context.Background())
 }
+
+// quietLogger logs only the errors of fx.
+func quietLogger() fx.Option {
+	return fx.WithLogger(
+		func() fxevent.Logger {
+			cfg := zap.NewDevelopmentConfig()
+			cfg.Level = zap.NewAtomicLevelAt(zapcore.ErrorLevel)
+			cfg.DisableCaller = true
+			logger, _ := cfg.Build()
+			return &fxevent.ZapLogger{Logger: logger}
+		},
+	)
+}
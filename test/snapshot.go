@@ -0,0 +1,139 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const updateFlag = "update"
+
+func init() {
+	if flag.Lookup(updateFlag) == nil {
+		flag.Bool(updateFlag, false, "update the golden files of the snapshot tests")
+	}
+}
+
+// Normalizer replaces the volatile values of a snapshot with placeholders.
+type Normalizer func(data []byte) []byte
+
+var (
+	timestampPattern = regexp.MustCompile(
+		`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`,
+	)
+	jsonDurationPattern = regexp.MustCompile(`("duration"\s*:\s*)("[^"]*"|[\d.e+-]+)`)
+	textDurationPattern = regexp.MustCompile(`\bduration=("[^"]*"|\S+)`)
+	// requestIDPattern matches the xid values generated by middleware.RequestID.
+	requestIDPattern = regexp.MustCompile(`\b[0-9a-v]{20}\b`)
+	// tracePattern matches the file paths of the traces added by errors.WithTrace and errtrace.
+	tracePattern = regexp.MustCompile(`[^\s"'=]*/([^/\s"'=]+\.go):\d+`)
+)
+
+// DefaultNormalizers replace timestamps, request IDs, durations of the request logs
+// and the directories and lines of the trace items.
+var DefaultNormalizers = []Normalizer{
+	NormalizeTimestamps,
+	NormalizeDurations,
+	NormalizeRequestIDs,
+	NormalizeTraces,
+}
+
+// NormalizeTimestamps replaces RFC 3339 timestamps with "<time>".
+func NormalizeTimestamps(data []byte) []byte {
+	return timestampPattern.ReplaceAll(data, []byte("<time>"))
+}
+
+// NormalizeDurations replaces the values of the "duration" JSON fields and log attributes with "<duration>".
+func NormalizeDurations(data []byte) []byte {
+	data = jsonDurationPattern.ReplaceAll(data, []byte(`${1}"<duration>"`))
+	return textDurationPattern.ReplaceAll(data, []byte("duration=<duration>"))
+}
+
+// NormalizeRequestIDs replaces the request IDs with "<request-id>".
+func NormalizeRequestIDs(data []byte) []byte {
+	return requestIDPattern.ReplaceAll(data, []byte("<request-id>"))
+}
+
+// NormalizeTraces replaces the file paths of the trace items with the file names and the lines with "<line>",
+// e.g. "/home/user/app/internal/blog/action.go:42" becomes "action.go:<line>".
+func NormalizeTraces(data []byte) []byte {
+	return tracePattern.ReplaceAll(data, []byte("${1}:<line>"))
+}
+
+// Normalize applies DefaultNormalizers and the given ones to the data.
+func Normalize(data []byte, normalizers ...Normalizer) []byte {
+	for _, normalize := range append(append([]Normalizer{}, DefaultNormalizers...), normalizers...) {
+		data = normalize(data)
+	}
+	return data
+}
+
+// MatchSnapshot compares the normalized data with the golden file testdata/{name}.golden of the tested package.
+// If the name is empty, the name of the test is used.
+// Run the tests with the -update flag to write the golden files.
+func MatchSnapshot(t testing.TB, name string, data []byte, normalizers ...Normalizer) {
+	t.Helper()
+	if name == "" {
+		name = t.Name()
+	}
+	path := goldenPath(name)
+	actual := Normalize(data, normalizers...)
+	if isUpdate() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("cannot create the golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, actual, 0644); err != nil {
+			t.Fatalf("cannot write the golden file: %v", err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cannot read the golden file %s, run the test with -update to create it: %v", path, err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf(
+			"the snapshot differs from the golden file %s, run the test with -update to update it\nexpected:\n%s\nactual:\n%s",
+			path,
+			expected,
+			actual,
+		)
+	}
+}
+
+// MatchJSONSnapshot formats the JSON with sorted keys and indents and compares it like MatchSnapshot.
+// Each line of the data may be a separate JSON document, e.g. structured log lines.
+func MatchJSONSnapshot(t testing.TB, name string, data []byte, normalizers ...Normalizer) {
+	t.Helper()
+	var formatted bytes.Buffer
+	encoder := json.NewEncoder(&formatted)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	for decoder.More() {
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			t.Fatalf("cannot decode the JSON snapshot: %v", err)
+		}
+		if err := encoder.Encode(value); err != nil {
+			t.Fatalf("cannot encode the JSON snapshot: %v", err)
+		}
+	}
+	MatchSnapshot(t, name, formatted.Bytes(), normalizers...)
+}
+
+func goldenPath(name string) string {
+	name = strings.NewReplacer("/", "__", " ", "_", ":", "_").Replace(name)
+	return filepath.Join("testdata", name+".golden")
+}
+
+func isUpdate() bool {
+	f := flag.Lookup(updateFlag)
+	return f != nil && f.Value.String() == "true"
+}
@@ -0,0 +1,79 @@
+package test_test
+
+import (
+	"bytes"
+	"log/slog"
+	netHttp "net/http"
+	"testing"
+
+	"github.com/go-modulus/modulus/errors"
+	"github.com/go-modulus/modulus/http"
+	"github.com/go-modulus/modulus/test"
+	"github.com/go-modulus/modulus/test/testhttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		data     string
+		expected string
+	}{
+		"timestamps": {
+			data:     `{"time":"2024-05-01T10:20:30.123456+02:00"} time=2024-05-01T10:20:30Z`,
+			expected: `{"time":"<time>"} time=<time>`,
+		},
+		"durations": {
+			data:     `{"duration":"1.234ms","ttl":"15s"} duration=25µs`,
+			expected: `{"duration":"<duration>","ttl":"15s"} duration=<duration>`,
+		},
+		"request ids": {
+			data:     `{"requestId":"cq7v2ih8gu6f0dkekn0g","hint":"Something went wrong (Code: cq7v2ih8gu6f0dkekn0g)"}`,
+			expected: `{"requestId":"<request-id>","hint":"Something went wrong (Code: <request-id>)"}`,
+		},
+		"traces": {
+			data:     "blog.(*Action).Create\n\t/home/user/app/internal/blog/action.go:42",
+			expected: "blog.(*Action).Create\n\taction.go:<line>",
+		},
+	}
+	for name, c := range cases {
+		t.Run(
+			name, func(t *testing.T) {
+				t.Parallel()
+				assert.Equal(t, c.expected, string(test.Normalize([]byte(c.data))))
+			},
+		)
+	}
+}
+
+func TestMatchJSONSnapshot(t *testing.T) {
+	t.Parallel()
+	t.Run(
+		"error response", func(t *testing.T) {
+			t.Parallel()
+			client := testhttp.NewClient(t, test.NewHarness(t, http.NewModule()))
+
+			resp := client.Get("/unknown").ExpectStatus(netHttp.StatusNotFound)
+
+			test.MatchJSONSnapshot(t, "", resp.Body)
+		},
+	)
+
+	t.Run(
+		"log lines", func(t *testing.T) {
+			t.Parallel()
+			var b bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&b, nil))
+			err := errors.WithTrace(errors.New("failed"))
+
+			logger.Info("handled request", slog.String("duration", "1.5ms"), slog.Any("trace", errors.Trace(err)))
+			logger.Error("cannot process", slog.String("error", err.Error()))
+
+			test.MatchJSONSnapshot(
+				t, "", b.Bytes(), func(data []byte) []byte {
+					return bytes.ReplaceAll(data, []byte("failed"), []byte("<error>"))
+				},
+			)
+		},
+	)
+}
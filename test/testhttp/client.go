@@ -0,0 +1,261 @@
+// Package testhttp sends requests to the routes of the http module in tests without binding a port.
+package testhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	modHttp "github.com/go-modulus/modulus/http"
+	"github.com/go-modulus/modulus/http/errhttp"
+	"github.com/go-modulus/modulus/test"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+)
+
+// Client sends requests to the handler and checks the responses. Failed expectations fail the test.
+type Client struct {
+	t       testing.TB
+	handler http.Handler
+	header  http.Header
+}
+
+// NewClient starts the harness and returns the client of its http routes.
+// The router, the middleware pipeline, the error pipeline and the routes are assembled like in the serve command.
+func NewClient(t testing.TB, h *test.Harness) *Client {
+	t.Helper()
+	var handler http.Handler
+	h.Options(
+		fx.Invoke(
+			func(params modHttp.HandlerParams) {
+				handler = modHttp.NewHandler(params)
+			},
+		),
+	).Start()
+	return NewHandlerClient(t, handler)
+}
+
+// NewHandlerClient returns the client of any handler.
+func NewHandlerClient(t testing.TB, handler http.Handler) *Client {
+	return &Client{
+		t:       t,
+		handler: handler,
+		header:  make(http.Header),
+	}
+}
+
+// WithHeader sets the header for all requests of the client.
+func (c *Client) WithHeader(key, value string) *Client {
+	c.header.Set(key, value)
+	return c
+}
+
+func (c *Client) Get(path string) *Request {
+	return c.Request(http.MethodGet, path)
+}
+
+func (c *Client) Post(path string) *Request {
+	return c.Request(http.MethodPost, path)
+}
+
+func (c *Client) Put(path string) *Request {
+	return c.Request(http.MethodPut, path)
+}
+
+func (c *Client) Patch(path string) *Request {
+	return c.Request(http.MethodPatch, path)
+}
+
+func (c *Client) Delete(path string) *Request {
+	return c.Request(http.MethodDelete, path)
+}
+
+// Request returns the request to the path. It is sent by Do or by the first expectation.
+func (c *Client) Request(method, path string) *Request {
+	return &Request{
+		client: c,
+		method: method,
+		path:   path,
+		header: c.header.Clone(),
+		query:  make(url.Values),
+		ctx:    context.Background(),
+	}
+}
+
+type Request struct {
+	client *Client
+	method string
+	path   string
+	header http.Header
+	query  url.Values
+	body   []byte
+	ctx    context.Context
+}
+
+func (r *Request) WithHeader(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// WithQuery adds the query parameter to the ones of the path.
+func (r *Request) WithQuery(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+func (r *Request) WithContext(ctx context.Context) *Request {
+	r.ctx = ctx
+	return r
+}
+
+// WithBody sets the body with the content type.
+func (r *Request) WithBody(contentType string, body []byte) *Request {
+	r.header.Set("Content-Type", contentType)
+	r.body = body
+	return r
+}
+
+// WithJSON sets the body to the value encoded in JSON.
+func (r *Request) WithJSON(value any) *Request {
+	r.client.t.Helper()
+	body, err := json.Marshal(value)
+	if err != nil {
+		r.client.t.Fatalf("cannot encode the request body: %v", err)
+	}
+	return r.WithBody("application/json", body)
+}
+
+// Do sends the request.
+func (r *Request) Do() *Response {
+	r.client.t.Helper()
+	req := httptest.NewRequestWithContext(r.ctx, r.method, r.path, bytes.NewReader(r.body))
+	if len(r.query) > 0 {
+		query := req.URL.Query()
+		for key, values := range r.query {
+			for _, value := range values {
+				query.Add(key, value)
+			}
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	recorder := httptest.NewRecorder()
+	r.client.handler.ServeHTTP(recorder, req)
+	result := recorder.Result()
+	body, _ := io.ReadAll(result.Body)
+	return &Response{
+		t:      r.client.t,
+		Status: result.StatusCode,
+		Header: result.Header,
+		Body:   body,
+	}
+}
+
+// ExpectStatus sends the request and checks the status of the response.
+func (r *Request) ExpectStatus(status int) *Response {
+	r.client.t.Helper()
+	return r.Do().ExpectStatus(status)
+}
+
+type Response struct {
+	t      testing.TB
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Error is an error of the JSON envelope written by errhttp.SendError.
+// The problem details written by errhttp.ProblemErrorWriter are converted to it too.
+type Error struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code string            `json:"code"`
+		Meta map[string]string `json:"meta"`
+	} `json:"extensions"`
+}
+
+func (r *Response) ExpectStatus(status int) *Response {
+	r.t.Helper()
+	assert.Equal(r.t, status, r.Status, "unexpected status, the body is %s", r.Body)
+	return r
+}
+
+func (r *Response) ExpectHeader(key, value string) *Response {
+	r.t.Helper()
+	assert.Equal(r.t, value, r.Header.Get(key), "unexpected header %s", key)
+	return r
+}
+
+// ExpectJSON checks that the body is equal to the expected JSON ignoring formatting.
+func (r *Response) ExpectJSON(expected string) *Response {
+	r.t.Helper()
+	assert.JSONEq(r.t, expected, string(r.Body))
+	return r
+}
+
+// ExpectErrorCode checks that the response is an error envelope or problem details with the error code, e.g. "not found".
+func (r *Response) ExpectErrorCode(code string) *Response {
+	r.t.Helper()
+	errs := r.Errors()
+	if assert.NotEmpty(r.t, errs, "the response is not an error, the body is %s", r.Body) {
+		assert.Equal(r.t, code, errs[0].Extensions.Code)
+	}
+	return r
+}
+
+// ExpectErrorMessage checks the hint of the error in the error envelope or the detail of the problem details.
+func (r *Response) ExpectErrorMessage(message string) *Response {
+	r.t.Helper()
+	errs := r.Errors()
+	if assert.NotEmpty(r.t, errs, "the response is not an error, the body is %s", r.Body) {
+		assert.Equal(r.t, message, errs[0].Message)
+	}
+	return r
+}
+
+// Errors returns the errors of the error envelope.
+// For application/problem+json the problem details are returned as one error: the detail is the message,
+// and the invalid fields of a validation error are added to the meta.
+// It is empty if the body is not an error envelope or problem details.
+func (r *Response) Errors() []Error {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == errhttp.ProblemMediaType {
+		return r.problemErrors()
+	}
+	var envelope struct {
+		Errors []Error `json:"errors"`
+	}
+	_ = json.Unmarshal(r.Body, &envelope)
+	return envelope.Errors
+}
+
+func (r *Response) problemErrors() []Error {
+	var problem errhttp.Problem
+	if err := json.Unmarshal(r.Body, &problem); err != nil || problem.Code == "" {
+		return nil
+	}
+	e := Error{Message: problem.Detail}
+	e.Extensions.Code = problem.Code
+	e.Extensions.Meta = problem.Meta
+	if len(problem.Errors) > 0 && e.Extensions.Meta == nil {
+		e.Extensions.Meta = make(map[string]string, len(problem.Errors))
+	}
+	for _, field := range problem.Errors {
+		e.Extensions.Meta[field.Field] = field.Detail
+	}
+	return []Error{e}
+}
+
+// DecodeJSON decodes the body into the target. The test fails if the body is not a valid JSON.
+func (r *Response) DecodeJSON(target any) *Response {
+	r.t.Helper()
+	assert.NoError(r.t, json.Unmarshal(r.Body, target), "the body is %s", r.Body)
+	return r
+}
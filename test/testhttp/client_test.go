@@ -0,0 +1,98 @@
+package testhttp_test
+
+import (
+	"encoding/json"
+	netHttp "net/http"
+	"testing"
+
+	"github.com/go-modulus/modulus/errors/erruser"
+	"github.com/go-modulus/modulus/http"
+	"github.com/go-modulus/modulus/http/errhttp"
+	"github.com/go-modulus/modulus/test"
+	"github.com/go-modulus/modulus/test/testhttp"
+	"github.com/stretchr/testify/assert"
+)
+
+var errNameRequired = erruser.New("name required", "Name is required")
+
+func newHelloRoute() http.RouteProvider {
+	return http.ProvideRoute(
+		netHttp.MethodPost, "/hello", func(w netHttp.ResponseWriter, req *netHttp.Request) error {
+			var input struct {
+				Name string `json:"name"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+				return err
+			}
+			if input.Name == "" {
+				return errNameRequired
+			}
+			w.Header().Set("Content-Type", "application/json")
+			return json.NewEncoder(w).Encode(
+				map[string]string{
+					"greeting": "Hello, " + input.Name,
+					"lang":     req.URL.Query().Get("lang"),
+					"token":    req.Header.Get("X-Token"),
+				},
+			)
+		},
+	)
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+	client := testhttp.NewClient(t, test.NewHarness(t, http.NewModule().AddProviders(newHelloRoute))).
+		WithHeader("X-Token", "secret")
+
+	t.Run(
+		"send the request to the route", func(t *testing.T) {
+			t.Parallel()
+			client.Post("/hello").
+				WithJSON(map[string]string{"name": "Bob"}).
+				WithQuery("lang", "en").
+				ExpectStatus(netHttp.StatusOK).
+				ExpectHeader("Content-Type", "application/json").
+				ExpectJSON(`{"greeting": "Hello, Bob", "lang": "en", "token": "secret"}`)
+		},
+	)
+
+	t.Run(
+		"read the error envelope", func(t *testing.T) {
+			t.Parallel()
+			resp := client.Post("/hello").
+				WithJSON(map[string]string{}).
+				ExpectStatus(netHttp.StatusBadRequest).
+				ExpectErrorCode("name required").
+				ExpectErrorMessage("Name is required")
+
+			assert.Len(t, resp.Errors(), 1)
+		},
+	)
+
+	t.Run(
+		"read the problem details", func(t *testing.T) {
+			t.Parallel()
+			resp := client.Post("/hello").
+				WithHeader("Accept", errhttp.ProblemMediaType).
+				WithJSON(map[string]string{}).
+				ExpectStatus(netHttp.StatusBadRequest).
+				ExpectHeader("Content-Type", errhttp.ProblemMediaType).
+				ExpectErrorCode("name required").
+				ExpectErrorMessage("Name is required")
+
+			assert.Len(t, resp.Errors(), 1)
+		},
+	)
+
+	t.Run(
+		"use the router of the module", func(t *testing.T) {
+			t.Parallel()
+			client.Get("/unknown").
+				ExpectStatus(netHttp.StatusNotFound).
+				ExpectErrorCode("not found")
+			client.Get("/hello").
+				ExpectStatus(netHttp.StatusMethodNotAllowed).
+				ExpectErrorCode("method not allowed")
+		},
+	)
+}